@@ -0,0 +1,269 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+/* Arrays */
+
+/*	Arrays are growable and mutable. Like dictionaries, they are shared rather than copied: storing
+	an array in a variable, reading the variable back, or using dup all give the same array, and
+	changes made through one are seen by all of them. Use clone to get an independent copy. */
+type langObjectArray struct {
+	elements []langObject
+}
+
+func (l *langObjectArray) getType() langObjectType {
+	return objectTypeArray
+}
+
+func (l *langObjectArray) getValue() interface{} {
+	return l.elements
+}
+
+func (l *langObjectArray) setValue(elements interface{}) {
+	l.elements = elements.([]langObject)
+}
+
+func (l *langObjectArray) toString() string {
+	elements := make([]string, 0)
+
+	for _, obj := range l.elements {
+		elements = append(elements, literalString(obj))
+	}
+
+	if len(elements) == 0 {
+		return "<Array>"
+	}
+
+	return "<Array: " + strings.Join(elements, " ") + ">"
+}
+
+func (l *langObjectArray) copy() langObject {
+	return l
+}
+
+func (l *langObjectArray) equals(o langObject) (bool, error) {
+	if o.getType() != objectTypeArray {
+		return false, nil
+	}
+
+	return newList(l.elements).equals(newList(o.(*langObjectArray).elements))
+}
+
+func (l *langObjectArray) greaterThan(o langObject) (bool, error) {
+	return unordered(l, o, "greater than", "array")
+}
+
+func (l *langObjectArray) lessThan(o langObject) (bool, error) {
+	return unordered(l, o, "less than", "array")
+}
+
+/* Converts a number into an index, allowing the length itself if end is true (as for slices) */
+func arrayIndex(obj langObject, length int, end bool) (int, error) {
+
+	if obj.getType() != objectTypeNumber {
+		return 0, errors.New("Expected, but did not receive a number as an array index.")
+	}
+
+	n := obj.(*langObjectNumber).val
+
+	if n != math.Trunc(n) || n < 0 || int(n) > length || (int(n) == length && !end) {
+		return 0, fmt.Errorf("Index %v is out of range for an array of length %d.", n, length)
+	}
+
+	return int(n), nil
+}
+
+func (l *langObjectArray) at(index langObject) (langObject, error) {
+	i, err := arrayIndex(index, len(l.elements), false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return l.elements[i], nil
+}
+
+func (s *stack) popArray() (*langObjectArray, error) {
+	obj, err := s.pop()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if obj.getType() != objectTypeArray {
+		return nil, errors.New("Expected, but did not receive an array.")
+	}
+
+	return obj.(*langObjectArray), nil
+}
+
+func performArrayOperation(typ operationType, s *stack, v *variableScope, st *symbolTable) error {
+
+	switch typ {
+	case operationTypeArrayNew:
+		s.push(&langObjectArray{make([]langObject, 0),})
+	case operationTypeGet:
+		index, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		array, err2 := s.popArray()
+
+		if err2 != nil {
+			return err2
+		}
+
+		value, err3 := array.at(index)
+
+		if err3 != nil {
+			return err3
+		}
+
+		s.push(value)
+	case operationTypeArraySet:
+		value, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		index, err2 := s.pop()
+
+		if err2 != nil {
+			return err2
+		}
+
+		array, err3 := s.popArray()
+
+		if err3 != nil {
+			return err3
+		}
+
+		i, err4 := arrayIndex(index, len(array.elements), false)
+
+		if err4 != nil {
+			return err4
+		}
+
+		array.elements[i] = value
+
+		s.push(array)
+	case operationTypeArrayPush:
+		value, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		array, err2 := s.popArray()
+
+		if err2 != nil {
+			return err2
+		}
+
+		array.elements = append(array.elements, value)
+
+		s.push(array)
+	case operationTypeArrayPop:
+		array, err1 := s.popArray()
+
+		if err1 != nil {
+			return err1
+		}
+
+		if len(array.elements) == 0 {
+			return errors.New("Unable to pop from an empty array.")
+		}
+
+		value := array.elements[len(array.elements) - 1]
+		array.elements = array.elements[:len(array.elements) - 1]
+
+		s.push(array)
+		s.push(value)
+	case operationTypeArraySlice:
+		endObj, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		startObj, err2 := s.pop()
+
+		if err2 != nil {
+			return err2
+		}
+
+		array, err3 := s.popArray()
+
+		if err3 != nil {
+			return err3
+		}
+
+		start, err4 := arrayIndex(startObj, len(array.elements), true)
+
+		if err4 != nil {
+			return err4
+		}
+
+		end, err5 := arrayIndex(endObj, len(array.elements), true)
+
+		if err5 != nil {
+			return err5
+		}
+
+		if end < start {
+			return fmt.Errorf("Slice end %d comes before its start %d.", end, start)
+		}
+
+		/* A slice is a new array, and does not share its elements with the original */
+		s.push(&langObjectArray{append([]langObject{}, array.elements[start:end]...),})
+	case operationTypeListToArray:
+		list, err1 := s.popList()
+
+		if err1 != nil {
+			return err1
+		}
+
+		s.push(&langObjectArray{list.elements(),})
+	case operationTypeArrayToList:
+		array, err1 := s.popArray()
+
+		if err1 != nil {
+			return err1
+		}
+
+		s.push(newList(array.elements))
+	case operationTypeClone:
+		obj, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		switch obj.getType() {
+		case objectTypeArray:
+			s.push(&langObjectArray{append([]langObject{}, obj.(*langObjectArray).elements...),})
+		case objectTypeDict:
+			dict := newDict()
+
+			for i, key := range obj.(*langObjectDict).keys {
+				dict.put(key, obj.(*langObjectDict).values[i])
+			}
+
+			s.push(dict)
+		default:
+			return errors.New("Expected, but did not receive an array or a dictionary.")
+		}
+	default:
+		return errors.New("Invalid array operation.")
+	}
+
+	return nil
+}
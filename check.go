@@ -0,0 +1,687 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*	The checker walks a parsed program without running it. It simulates the stack, keeping track of
+	the types of the values on it where they are known, and of the stack effects of code blocks. */
+
+const checkAnyType langObjectType = -1
+
+/* The types of the items an operation takes from the stack (deepest first) and leaves on it */
+type operationSignature struct {
+	inputs []langObjectType
+	outputs []langObjectType
+}
+
+func signature(inputs []langObjectType, outputs ...langObjectType) operationSignature {
+	return operationSignature{inputs, outputs,}
+}
+
+type types []langObjectType
+
+/* Operations that are not listed here have effects that depend on their arguments, and are handled by the checker itself */
+var operationSignatures = map[operationType]operationSignature{
+	operationTypeAdd: signature(types{checkAnyType, checkAnyType}, checkAnyType),
+	operationTypeSubtract: signature(types{objectTypeNumber, objectTypeNumber}, objectTypeNumber),
+	operationTypeMultiply: signature(types{objectTypeNumber, objectTypeNumber}, objectTypeNumber),
+	operationTypeDivide: signature(types{objectTypeNumber, objectTypeNumber}, objectTypeNumber),
+	operationTypeAssign: signature(types{checkAnyType, checkAnyType}),
+	operationTypeLocalAssign: signature(types{checkAnyType, checkAnyType}),
+	operationTypeAt: signature(types{objectTypeReference}, checkAnyType),
+	operationTypeNot: signature(types{objectTypeBoolean}, objectTypeBoolean),
+	operationTypeEquals: signature(types{checkAnyType, checkAnyType}, objectTypeBoolean),
+	operationTypeGreater: signature(types{checkAnyType, checkAnyType}, objectTypeBoolean),
+	operationTypeLess: signature(types{checkAnyType, checkAnyType}, objectTypeBoolean),
+	operationTypeGreaterEquals: signature(types{checkAnyType, checkAnyType}, objectTypeBoolean),
+	operationTypeLessEquals: signature(types{checkAnyType, checkAnyType}, objectTypeBoolean),
+	operationTypeCons: signature(types{objectTypeList, checkAnyType}, objectTypeList),
+	operationTypeListEmpty: signature(types{objectTypeList}, objectTypeBoolean),
+	operationTypeListSplit: signature(types{objectTypeList}, objectTypeList, checkAnyType),
+	operationTypePop: signature(types{checkAnyType}),
+	operationTypeListLength: signature(types{checkAnyType}, objectTypeNumber),
+	operationTypeListReverse: signature(types{objectTypeList}, objectTypeList),
+	operationTypeListMap: signature(types{objectTypeList, objectTypeCodeBlock}, objectTypeList),
+	operationTypeListFilter: signature(types{objectTypeList, objectTypeCodeBlock}, objectTypeList),
+	operationTypeListFold: signature(types{objectTypeList, checkAnyType, objectTypeCodeBlock}, checkAnyType),
+	operationTypeListReduce: signature(types{objectTypeList, objectTypeCodeBlock}, checkAnyType),
+	operationTypeListNth: signature(types{objectTypeList, objectTypeNumber}, checkAnyType),
+	operationTypeListAppend: signature(types{objectTypeList, checkAnyType}, objectTypeList),
+	operationTypeListConcat: signature(types{objectTypeList, objectTypeList}, objectTypeList),
+	operationTypeListRange: signature(types{objectTypeNumber, objectTypeNumber}, objectTypeList),
+	operationTypeListZip: signature(types{objectTypeList, objectTypeList}, objectTypeList),
+	operationTypeListAny: signature(types{objectTypeList, objectTypeCodeBlock}, objectTypeBoolean),
+	operationTypeListAll: signature(types{objectTypeList, objectTypeCodeBlock}, objectTypeBoolean),
+	operationTypeDictPut: signature(types{objectTypeDict, checkAnyType, checkAnyType}, objectTypeDict),
+	operationTypeGet: signature(types{checkAnyType, checkAnyType}, checkAnyType),
+	operationTypeDictHas: signature(types{objectTypeDict, checkAnyType}, objectTypeBoolean),
+	operationTypeDictDelete: signature(types{objectTypeDict, checkAnyType}, objectTypeDict),
+	operationTypeDictKeys: signature(types{objectTypeDict}, objectTypeList),
+	operationTypeDictValues: signature(types{objectTypeDict}, objectTypeList),
+	operationTypeDictSize: signature(types{objectTypeDict}, objectTypeNumber),
+	operationTypeDictEach: signature(types{objectTypeDict, objectTypeCodeBlock}),
+	operationTypeArrayNew: signature(types{}, objectTypeArray),
+	operationTypeArraySet: signature(types{objectTypeArray, objectTypeNumber, checkAnyType}, objectTypeArray),
+	operationTypeArrayPush: signature(types{objectTypeArray, checkAnyType}, objectTypeArray),
+	operationTypeArrayPop: signature(types{objectTypeArray}, objectTypeArray, checkAnyType),
+	operationTypeArraySlice: signature(types{objectTypeArray, objectTypeNumber, objectTypeNumber}, objectTypeArray),
+	operationTypeListToArray: signature(types{objectTypeList}, objectTypeArray),
+	operationTypeArrayToList: signature(types{objectTypeArray}, objectTypeList),
+	operationTypeClone: signature(types{checkAnyType}, checkAnyType),
+	operationTypeDefineRecord: signature(types{objectTypeSymbol, objectTypeList}),
+	operationTypeRecordWith: signature(types{objectTypeRecord, objectTypeSymbol, checkAnyType}, objectTypeRecord),
+	operationTypeRecordIs: signature(types{checkAnyType, objectTypeRecordType}, objectTypeBoolean),
+	operationTypeTypeOf: signature(types{checkAnyType}, objectTypeSymbol),
+	operationTypeIsNumber: signature(types{checkAnyType}, objectTypeBoolean),
+	operationTypeIsString: signature(types{checkAnyType}, objectTypeBoolean),
+	operationTypeIsBoolean: signature(types{checkAnyType}, objectTypeBoolean),
+	operationTypeIsBlock: signature(types{checkAnyType}, objectTypeBoolean),
+	operationTypeIsList: signature(types{checkAnyType}, objectTypeBoolean),
+	operationTypeIsReference: signature(types{checkAnyType}, objectTypeBoolean),
+	operationTypeIsError: signature(types{checkAnyType}, objectTypeBoolean),
+	operationTypeIsSymbol: signature(types{checkAnyType}, objectTypeBoolean),
+	operationTypeIsDict: signature(types{checkAnyType}, objectTypeBoolean),
+	operationTypeIsArray: signature(types{checkAnyType}, objectTypeBoolean),
+	operationTypeIsRecord: signature(types{checkAnyType}, objectTypeBoolean),
+	operationTypeDepth: signature(types{}, objectTypeNumber),
+	operationTypeExport: signature(types{checkAnyType}),
+	operationTypePrint: signature(types{checkAnyType}),
+	operationTypePrintLine: signature(types{checkAnyType}),
+	operationTypeEmit: signature(types{objectTypeString}),
+	operationTypeReadLine: signature(types{}, checkAnyType),
+	operationTypeReadAll: signature(types{}, objectTypeString),
+	operationTypeReadFile: signature(types{objectTypeString}, checkAnyType),
+	operationTypeWriteFile: signature(types{objectTypeString, objectTypeString}, checkAnyType),
+	operationTypeAppendFile: signature(types{objectTypeString, objectTypeString}, checkAnyType),
+	operationTypeListDir: signature(types{objectTypeString}, checkAnyType),
+	operationTypeExists: signature(types{objectTypeString}, objectTypeBoolean),
+	operationTypeStat: signature(types{objectTypeString}, checkAnyType),
+	operationTypeMakeDir: signature(types{objectTypeString}, checkAnyType),
+	operationTypeRemove: signature(types{objectTypeString}, checkAnyType),
+	operationTypeGlob: signature(types{objectTypeString}, checkAnyType),
+	operationTypeStringToNumber: signature(types{objectTypeString}, checkAnyType),
+	operationTypeJSONParse: signature(types{objectTypeString}, checkAnyType),
+	operationTypeJSONStringify: signature(types{checkAnyType}, objectTypeString),
+	operationTypeJSONPretty: signature(types{checkAnyType, checkAnyType}, objectTypeString),
+	operationTypeCSVRead: signature(types{objectTypeString, objectTypeDict}, checkAnyType),
+	operationTypeCSVReadFile: signature(types{objectTypeString, objectTypeDict}, checkAnyType),
+	operationTypeCSVWrite: signature(types{checkAnyType, objectTypeDict}, checkAnyType),
+	operationTypeRegexMatch: signature(types{objectTypeString, objectTypeString}, objectTypeBoolean),
+	operationTypeRegexFind: signature(types{objectTypeString, objectTypeString}, checkAnyType),
+	operationTypeRegexFindAll: signature(types{objectTypeString, objectTypeString}, objectTypeList),
+	operationTypeRegexReplace: signature(types{objectTypeString, objectTypeString, checkAnyType}, objectTypeString),
+	operationTypeRegexSplit: signature(types{objectTypeString, objectTypeString}, objectTypeList),
+	operationTypeGetenv: signature(types{objectTypeString}, checkAnyType),
+	operationTypeSetenv: signature(types{objectTypeString, objectTypeString}),
+	operationTypeNow: signature(types{}, objectTypeNumber),
+	operationTypeTimeFormat: signature(types{objectTypeNumber, objectTypeString}, objectTypeString),
+	operationTypeTimeParse: signature(types{objectTypeString, objectTypeString}, checkAnyType),
+	operationTypeSleep: signature(types{objectTypeNumber}),
+	operationTypeExit: signature(types{objectTypeNumber}),
+	operationTypeArgs: signature(types{}, objectTypeList),
+	operationTypeExecLines: signature(types{objectTypeList, objectTypeCodeBlock}, objectTypeString, checkAnyType),
+	operationTypeRand: signature(types{}, objectTypeNumber),
+	operationTypeRandInt: signature(types{objectTypeNumber, objectTypeNumber}, objectTypeNumber),
+	operationTypeShuffle: signature(types{checkAnyType}, checkAnyType),
+	operationTypeChoice: signature(types{checkAnyType}, checkAnyType),
+	operationTypeSeed: signature(types{objectTypeNumber}),
+}
+
+/* Operations that rearrange the stack: how many items they take, and which of them they leave (deepest first) */
+type stackShuffle struct {
+	inputs int
+	outputs []int
+}
+
+var stackShuffles = map[operationType]stackShuffle{
+	operationTypeDuplicate: {1, []int{0, 0},},
+	operationTypeSwap: {2, []int{1, 0},},
+	operationTypeOver: {2, []int{0, 1, 0},},
+	operationTypeRot: {3, []int{1, 2, 0},},
+	operationTypeReverseRot: {3, []int{2, 0, 1},},
+	operationTypeNip: {2, []int{1},},
+	operationTypeTuck: {2, []int{1, 0, 1},},
+	operationTypeTwoDup: {2, []int{0, 1, 0, 1},},
+	operationTypeTwoDrop: {2, []int{},},
+}
+
+/* The number of items a piece of code takes from the stack, and how many it leaves in their place */
+type stackCounts struct {
+	inputs int
+	outputs int
+}
+
+/* A value on the simulated stack. counts is the stack effect of a code block, if it is known. */
+type checkValue struct {
+	typ langObjectType
+	counts *stackCounts
+}
+
+var unknownValue = checkValue{checkAnyType, nil,}
+
+type diagnosticKind int
+
+const (
+	diagnosticEffect diagnosticKind = iota
+	diagnosticUnderflow
+	diagnosticType
+	diagnosticUndefined
+)
+
+type diagnostic struct {
+	kind diagnosticKind
+	block string
+	line int
+	message string
+}
+
+func (d *diagnostic) toString() string {
+	return fmt.Sprintf("In %s: %s", d.block, d.message)
+}
+
+/*	The names visible in a code block. Names are collected from the whole block before it is
+	checked, so that a block may use a variable that its parent assigns after the block is written.
+	Local variables (from lasn) are not visible to nested blocks. If a block includes or imports
+	another file, any name could be defined, so open is set. */
+type checkScope struct {
+	names map[string]bool
+	locals map[string]bool
+	values map[string]checkValue
+	parent *checkScope
+	open bool
+}
+
+func (c *checkScope) defined(name string, localsVisible bool) bool {
+	if c.open || c.names[name] || (localsVisible && c.locals[name]) {
+		return true
+	}
+
+	if c.parent != nil {
+		return c.parent.defined(name, false)
+	}
+
+	return false
+}
+
+func isOperation(o langObject, typ operationType) bool {
+	return o.getType() == objectTypeOperation && o.(*langObjectOperation).val == typ
+}
+
+/* Returns the name assigned to by the asn or lasn at code[i], if there is one */
+func assignedName(code []langObject, i int) (string, bool) {
+	if i < 1 || (!isOperation(code[i], operationTypeAssign) && !isOperation(code[i], operationTypeLocalAssign)) {
+		return "", false
+	}
+
+	if code[i - 1].getType() != objectTypeIdentifier || code[i - 1].(*langObjectIdentifier).typ != identifierReference {
+		return "", false
+	}
+
+	return code[i - 1].(*langObjectIdentifier).name, true
+}
+
+func newCheckScope(code []langObject, parent *checkScope) *checkScope {
+
+	scope := &checkScope{make(map[string]bool), make(map[string]bool), make(map[string]checkValue), parent, false,}
+
+	for i, o := range code {
+
+		if name, ok := assignedName(code, i); ok {
+			if isOperation(o, operationTypeLocalAssign) {
+				scope.locals[name] = true
+			} else {
+				scope.names[name] = true
+			}
+		}
+
+		/* .Name [ ... ] defrecord defines the variable Name */
+		if isOperation(o, operationTypeDefineRecord) {
+			depth := 0
+
+			for j := i - 1; j >= 0; j-- {
+				if isOperation(code[j], operationTypeListEnd) {
+					depth++
+				} else if isOperation(code[j], operationTypeListBegin) {
+					depth--
+				}
+
+				if depth == 0 {
+					if j > 0 {
+						if name, ok := symbolName(code[j - 1]); ok {
+							scope.names[name] = true
+						}
+					}
+
+					break
+				}
+			}
+		}
+
+		if isOperation(o, operationTypeInclude) || isOperation(o, operationTypeIncludeOnce) || isOperation(o, operationTypeImport) {
+			scope.open = true
+		}
+	}
+
+	return scope
+}
+
+type checker struct {
+	diagnostics []diagnostic
+}
+
+func (c *checker) report(kind diagnosticKind, label string, line int, format string, args ...interface{}) {
+	c.diagnostics = append(c.diagnostics, diagnostic{kind, label, line, fmt.Sprintf(format, args...),})
+}
+
+/* Describes a type for a diagnostic, as in "expects a number" */
+func checkTypeName(typ langObjectType) string {
+	switch typ {
+	case objectTypeCodeBlock:
+		return "code block"
+	case objectTypeDict:
+		return "dictionary"
+	case objectTypeRecordType:
+		return "record type"
+	}
+
+	return objectTypeName(typ)
+}
+
+/*	Checks a block of code, reporting problems as it goes, and returns the stack effect of the
+	code: the declared effect if there is one, otherwise the inferred effect, or nil if the effect
+	depends on things that are only known at run time.
+
+	At the top level of a program the stack starts out empty, so taking items from it is an
+	underflow. */
+func (c *checker) check(codeBlock *langObjectCodeBlock, scope *checkScope, label string, topLevel bool) *stackCounts {
+
+	code, declared := codeBlock.code, codeBlock.effect
+
+	simulated := make([]checkValue, 0)
+	marks := make([]int, 0)
+	inputs := 0
+
+	/* Once the checker reaches something whose effect it cannot work out, it no longer knows what
+	   is beneath the values it has simulated since */
+	lost := false
+	underflowReported := false
+
+	/* The line of the code being checked, when it came from a file */
+	line := 0
+
+	if len(codeBlock.lines) > 0 {
+		line = codeBlock.lines[0]
+	}
+
+	report := func(kind diagnosticKind, format string, args ...interface{}) {
+		c.report(kind, label, line, format, args...)
+	}
+
+	lose := func() {
+		lost = true
+		simulated = simulated[:0]
+
+		for i := range marks {
+			marks[i] = 0
+		}
+	}
+
+	push := func(values ...checkValue) {
+		simulated = append(simulated, values...)
+	}
+
+	pop := func(name string) checkValue {
+		if len(simulated) > 0 {
+			top := simulated[len(simulated) - 1]
+			simulated = simulated[:len(simulated) - 1]
+
+			return top
+		}
+
+		if lost || underflowReported {
+			return unknownValue
+		}
+
+		switch {
+		case topLevel:
+			report(diagnosticUnderflow, "Stack underflow: %s takes more items than there are on the stack.", name)
+			underflowReported = true
+		case declared != nil && inputs >= len(declared.inputs):
+			report(diagnosticEffect, "Code block %s takes more items from the stack than the %d inputs it declares.", declared.toString(), len(declared.inputs))
+			underflowReported = true
+		default:
+			inputs++
+		}
+
+		return unknownValue
+	}
+
+	expect := func(value checkValue, typ langObjectType, name string) {
+		if typ != checkAnyType && value.typ != checkAnyType && value.typ != typ {
+			report(diagnosticType, "%s expects a %s, but is given a %s.", name, checkTypeName(typ), checkTypeName(value.typ))
+		}
+	}
+
+	apply := func(counts *stackCounts, name string) {
+		for i := 0; i < counts.inputs; i++ {
+			pop(name)
+		}
+
+		for i := 0; i < counts.outputs; i++ {
+			push(unknownValue)
+		}
+	}
+
+	/* Parameters are taken from the stack as the block is called */
+	for range codeBlock.params {
+		pop("the parameters " + codeBlock.paramsString())
+	}
+
+	for i, o := range code {
+
+		if i < len(codeBlock.lines) {
+			line = codeBlock.lines[i]
+		}
+
+		switch o.getType() {
+		case objectTypeCodeBlock:
+			codeBlock := o.(*langObjectCodeBlock)
+			childScope := newCheckScope(codeBlock.code, scope)
+
+			for _, param := range codeBlock.params {
+				childScope.names[param] = true
+			}
+			childLabel := "a code block in " + label
+
+			if i + 2 < len(code) {
+				if name, ok := assignedName(code, i + 2); ok {
+					childLabel = "'" + name + "'"
+				}
+			}
+
+			/* The variables of a for loop's initializer are visible to the loop's other blocks */
+			for j := i - 1; j >= i - 3 && j >= 0; j-- {
+				if j + 4 < len(code) && isOperation(code[j + 4], operationTypeFor) && code[j].getType() == objectTypeCodeBlock {
+					initScope := newCheckScope(code[j].(*langObjectCodeBlock).code, nil)
+
+					for name := range initScope.names {
+						childScope.names[name] = true
+					}
+
+					for name := range initScope.locals {
+						childScope.names[name] = true
+					}
+				}
+			}
+
+			counts := c.check(codeBlock, childScope, childLabel, false)
+
+			push(checkValue{objectTypeCodeBlock, counts,})
+		case objectTypeIdentifier:
+			ident := o.(*langObjectIdentifier)
+
+			switch ident.typ {
+			case identifierDefault:
+				if !scope.defined(ident.name, true) {
+					report(diagnosticUndefined, "Variable '%s' is not defined in this scope.", ident.name)
+				}
+
+				if value, ok := scope.values[ident.name]; ok {
+					push(value)
+				} else {
+					push(unknownValue)
+				}
+			case identifierReference:
+				push(unknownValue)
+			default:
+				lose()
+			}
+		case objectTypeOperation:
+			op := o.(*langObjectOperation)
+			name := "'" + op.name() + "'"
+
+			if sig, ok := operationSignatures[op.val]; ok {
+				/* Remember what is assigned to variables, so that calling them can be checked */
+				if assigned, isAssign := assignedName(code, i); isAssign && len(simulated) >= 2 {
+					scope.values[assigned] = simulated[len(simulated) - 2]
+				}
+
+				for j := len(sig.inputs) - 1; j >= 0; j-- {
+					expect(pop(name), sig.inputs[j], name)
+				}
+
+				for _, typ := range sig.outputs {
+					push(checkValue{typ, nil,})
+				}
+
+				continue
+			}
+
+			if shuffle, ok := stackShuffles[op.val]; ok {
+				values := make([]checkValue, shuffle.inputs)
+
+				for j := shuffle.inputs - 1; j >= 0; j-- {
+					values[j] = pop(name)
+				}
+
+				for _, j := range shuffle.outputs {
+					push(values[j])
+				}
+
+				continue
+			}
+
+			switch op.val {
+			case operationTypeDip, operationTypeKeep:
+				value := pop(name)
+				expect(value, objectTypeCodeBlock, name)
+
+				kept := pop(name)
+
+				/* keep runs the block on the item; dip runs it beneath the item */
+				if op.val == operationTypeKeep {
+					push(kept)
+				}
+
+				if value.counts == nil {
+					lose()
+				} else {
+					apply(value.counts, name)
+				}
+
+				push(kept)
+			case operationTypeBi:
+				second := pop(name)
+				expect(second, objectTypeCodeBlock, name)
+
+				first := pop(name)
+				expect(first, objectTypeCodeBlock, name)
+
+				kept := pop(name)
+
+				for _, value := range []checkValue{first, second} {
+					push(kept)
+
+					if value.counts == nil {
+						lose()
+					} else {
+						apply(value.counts, name)
+					}
+				}
+			case operationTypePick, operationTypeRoll:
+				expect(pop(name), objectTypeNumber, name)
+				lose()
+
+				if op.val == operationTypePick {
+					push(unknownValue)
+				}
+			case operationTypeExecute:
+				value := pop(name)
+				expect(value, objectTypeCodeBlock, name)
+
+				if value.counts == nil {
+					lose()
+				} else {
+					apply(value.counts, name)
+				}
+			case operationTypeIf:
+				expect(pop(name), objectTypeBoolean, name)
+
+				value := pop(name)
+				expect(value, objectTypeCodeBlock, name)
+
+				/* The block may or may not run, so it must leave the stack as deep as it found it */
+				if value.counts == nil || value.counts.inputs != value.counts.outputs {
+					lose()
+				} else {
+					apply(value.counts, name)
+				}
+			case operationTypeFor:
+				blocks := make([]checkValue, 4)
+
+				for j := 3; j >= 0; j-- {
+					blocks[j] = pop(name)
+					expect(blocks[j], objectTypeCodeBlock, name)
+				}
+
+				initial, condition, body, after := blocks[0].counts, blocks[1].counts, blocks[2].counts, blocks[3].counts
+
+				if initial == nil || condition == nil || body == nil || after == nil {
+					lose()
+				} else if condition.outputs - condition.inputs != 1 || body.outputs != body.inputs || after.outputs != after.inputs {
+					lose()
+				} else {
+					apply(initial, name)
+				}
+			case operationTypeListSort:
+				value := pop(name)
+
+				switch value.typ {
+				case objectTypeCodeBlock:
+					expect(pop(name), objectTypeList, name)
+					push(checkValue{objectTypeList, nil,})
+				case objectTypeList:
+					push(checkValue{objectTypeList, nil,})
+				case checkAnyType:
+					lose()
+					push(checkValue{objectTypeList, nil,})
+				default:
+					expect(value, objectTypeList, name)
+					push(checkValue{objectTypeList, nil,})
+				}
+			case operationTypeExec:
+				/* The input for the command may be given above it */
+				value := pop(name)
+
+				switch value.typ {
+				case objectTypeString:
+					expect(pop(name), objectTypeList, name)
+				case checkAnyType:
+					lose()
+				default:
+					expect(value, objectTypeList, name)
+				}
+
+				push(checkValue{objectTypeString, nil,})
+				push(checkValue{objectTypeString, nil,})
+				push(unknownValue)
+			case operationTypeClear:
+				if topLevel {
+					simulated = simulated[:0]
+				} else {
+					lose()
+				}
+			case operationTypeListBegin, operationTypeDictBegin:
+				marks = append(marks, len(simulated))
+			case operationTypeListEnd, operationTypeDictEnd:
+				mark := 0
+
+				if len(marks) > 0 {
+					mark = marks[len(marks) - 1]
+					marks = marks[:len(marks) - 1]
+				}
+
+				if mark > len(simulated) {
+					mark = len(simulated)
+				}
+
+				simulated = simulated[:mark]
+
+				if isOperation(o, operationTypeListEnd) {
+					push(checkValue{objectTypeList, nil,})
+				} else {
+					push(checkValue{objectTypeDict, nil,})
+				}
+			case operationTypeFormat:
+				expect(pop(name), objectTypeString, name)
+				lose()
+				push(checkValue{objectTypeString, nil,})
+			case operationTypeListToStack:
+				expect(pop(name), objectTypeList, name)
+				lose()
+			case operationTypeStackToList:
+				expect(pop(name), objectTypeNumber, name)
+				lose()
+				push(checkValue{objectTypeList, nil,})
+			case operationTypeNewRecord:
+				expect(pop(name), objectTypeRecordType, name)
+				lose()
+				push(checkValue{objectTypeRecord, nil,})
+			default:
+				lose()
+			}
+		default:
+			push(checkValue{o.getType(), nil,})
+		}
+	}
+
+	if declared != nil {
+		if !lost && !underflowReported {
+			expected := len(declared.outputs) - len(declared.inputs)
+
+			if actual := len(simulated) - inputs; actual != expected {
+				report(diagnosticEffect, "Code block %s changes the stack depth by %+d, but its declaration says %+d.", declared.toString(), actual, expected)
+			}
+		}
+
+		return &stackCounts{len(declared.inputs), len(declared.outputs),}
+	}
+
+	if lost || underflowReported {
+		return nil
+	}
+
+	return &stackCounts{inputs, len(simulated),}
+}
+
+/* Checks a whole program, whose stack starts out empty */
+func checkProgram(program *langObjectCodeBlock) []diagnostic {
+	c := &checker{}
+
+	c.check(program, newCheckScope(program.code, nil), "the top level", true)
+
+	return c.diagnostics
+}
+
+/*	Checks the code blocks that declare their stack effect against the effect of their code, wherever
+	that can be worked out before the code runs. This is done whenever code is loaded. Since the code
+	may use variables that are already defined, and items that are already on the stack, nothing else
+	is reported. */
+func verifyStackEffects(program *langObjectCodeBlock) error {
+	c := &checker{}
+
+	scope := newCheckScope(program.code, nil)
+	scope.open = true
+
+	c.check(program, scope, "the top level", false)
+
+	for _, d := range c.diagnostics {
+		if d.kind == diagnosticEffect {
+			return atLine(program.file, d.line, errors.New(d.message))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/* CSV */
+
+/*	Rows are read as lists of strings. With the .header option, the first row names the columns, and
+	each of the other rows becomes a dictionary from column names to values instead. */
+type csvOptions struct {
+	header bool
+	delimiter rune
+	lazyQuotes bool
+}
+
+/* Reads the options dictionary given to the CSV operations, such as #{ .header true .delimiter ";" } */
+func toCSVOptions(dict *langObjectDict) (csvOptions, error) {
+	options := csvOptions{false, ',', false,}
+
+	for i, key := range dict.keys {
+		name, _ := symbolName(key)
+		value := dict.values[i]
+
+		switch name {
+		case "header", "lazy-quotes":
+			if value.getType() != objectTypeBoolean {
+				return options, fmt.Errorf("The CSV option .%s must be true or false.", name)
+			}
+
+			if name == "header" {
+				options.header = value.(*langObjectBoolean).val
+			} else {
+				options.lazyQuotes = value.(*langObjectBoolean).val
+			}
+		case "delimiter":
+			if value.getType() != objectTypeString || utf8.RuneCountInString(value.(*langObjectString).val) != 1 {
+				return options, errors.New("The CSV option .delimiter must be a string of one character.")
+			}
+
+			options.delimiter, _ = utf8.DecodeRuneInString(value.(*langObjectString).val)
+		default:
+			return options, fmt.Errorf("Unknown CSV option %s.", literalString(key))
+		}
+	}
+
+	return options, nil
+}
+
+func readCSV(input io.Reader, options csvOptions) (langObject, error) {
+	reader := csv.NewReader(input)
+	reader.Comma = options.delimiter
+	reader.LazyQuotes = options.lazyQuotes
+
+	records, err := reader.ReadAll()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]langObject, 0)
+
+	if !options.header {
+		for _, record := range records {
+			rows = append(rows, stringList(record))
+		}
+
+		return newList(rows), nil
+	}
+
+	if len(records) == 0 {
+		return newList(rows), nil
+	}
+
+	for _, record := range records[1:] {
+		row := newDict()
+
+		for i, name := range records[0] {
+			row.put(&langObjectString{name,}, &langObjectString{record[i],})
+		}
+
+		rows = append(rows, row)
+	}
+
+	return newList(rows), nil
+}
+
+/* The text of a CSV field: strings as they are, and other values as they would be printed */
+func csvField(obj langObject) string {
+
+	switch obj.getType() {
+	case objectTypeString:
+		return obj.(*langObjectString).val
+	case objectTypeNumber:
+		return strconv.FormatFloat(obj.(*langObjectNumber).val, 'f', -1, 64)
+	case objectTypeSymbol:
+		return obj.(*langObjectSymbol).name
+	}
+
+	return obj.toString()
+}
+
+/* The elements of a row or of the list of rows, which may be a list or an array */
+func csvElements(obj langObject) ([]langObject, bool) {
+
+	switch obj.getType() {
+	case objectTypeList:
+		return obj.(*langObjectList).elements(), true
+	case objectTypeArray:
+		return obj.(*langObjectArray).elements, true
+	}
+
+	return nil, false
+}
+
+/* The records to write for a list of rows, which are lists, or dictionaries if there is a header */
+func csvRecords(rows []langObject, options csvOptions) ([][]string, error) {
+	records := make([][]string, 0)
+
+	/* With .header, the rows are dictionaries, and the keys of the first one give the columns */
+	var columns []langObject
+
+	if options.header && len(rows) > 0 {
+		if rows[0].getType() != objectTypeDict {
+			return nil, errors.New("Expected, but did not receive a dictionary for each row.")
+		}
+
+		columns = rows[0].(*langObjectDict).keys
+
+		header := make([]string, 0)
+
+		for _, column := range columns {
+			header = append(header, csvField(column))
+		}
+
+		records = append(records, header)
+	}
+
+	for _, row := range rows {
+		record := make([]string, 0)
+
+		if options.header {
+			if row.getType() != objectTypeDict {
+				return nil, errors.New("Expected, but did not receive a dictionary for each row.")
+			}
+
+			for _, column := range columns {
+				value, ok, _ := row.(*langObjectDict).get(column)
+
+				if ok {
+					record = append(record, csvField(value))
+				} else {
+					record = append(record, "")
+				}
+			}
+		} else {
+			fields, ok := csvElements(row)
+
+			if ok == false {
+				return nil, errors.New("Expected, but did not receive a list for each row.")
+			}
+
+			for _, field := range fields {
+				record = append(record, csvField(field))
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func writeCSV(records [][]string, options csvOptions) (string, error) {
+	b := &strings.Builder{}
+	writer := csv.NewWriter(b)
+	writer.Comma = options.delimiter
+
+	for _, record := range records {
+		err := writer.Write(record)
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+func performCSVOperation(typ operationType, s *stack, v *variableScope, st *symbolTable, in *interpreter) error {
+
+	dict, err1 := s.popDict()
+
+	if err1 != nil {
+		return err1
+	}
+
+	options, err2 := toCSVOptions(dict)
+
+	if err2 != nil {
+		return err2
+	}
+
+	switch typ {
+	case operationTypeCSVRead:
+		text, err3 := s.popString("a CSV string")
+
+		if err3 != nil {
+			return err3
+		}
+
+		/* Malformed CSV comes from outside the program, so it gives an error value */
+		s.pushResult(readCSV(strings.NewReader(text), options))
+	case operationTypeCSVReadFile:
+		if !in.allows(capabilityFiles) {
+			return errors.New("File system access is not allowed.")
+		}
+
+		path, err3 := s.popString("a path")
+
+		if err3 != nil {
+			return err3
+		}
+
+		file, err4 := os.Open(path)
+
+		if err4 != nil {
+			s.pushResult(nil, err4)
+			return nil
+		}
+
+		defer file.Close()
+
+		s.pushResult(readCSV(file, options))
+	case operationTypeCSVWrite:
+		obj, err3 := s.pop()
+
+		if err3 != nil {
+			return err3
+		}
+
+		rows, ok := csvElements(obj)
+
+		if ok == false {
+			return errors.New("Expected, but did not receive a list of rows.")
+		}
+
+		records, err4 := csvRecords(rows, options)
+
+		if err4 != nil {
+			return err4
+		}
+
+		/* The writer can still fail, for example on a delimiter it cannot use, which gives an error value */
+		text, err5 := writeCSV(records, options)
+		s.pushResult(&langObjectString{text,}, err5)
+	default:
+		return errors.New("Invalid CSV operation.")
+	}
+
+	return nil
+}
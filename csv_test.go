@@ -0,0 +1,105 @@
+package main
+
+import (
+	"testing"
+)
+
+/* Runs code on a stack holding only text, and returns what is left on top */
+func runCSV(text string, code string) (langObject, error) {
+	s, v, st, in := newTestProgram()
+	s.push(&langObjectString{text,})
+
+	err := evalString(code, "", s, v, st, in, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.pop()
+}
+
+/* Reading CSV and writing it back with the same options should give the same text and rows */
+func TestCSVRoundTrip(t *testing.T) {
+	tests := []struct {
+		options string
+		text string
+		written string
+	}{
+		{"#{ }", "a,b\n1,2\n", "a,b\n1,2\n"},
+		{"#{ }", "\"a,b\",\"say \"\"hi\"\"\"\n,\n", "\"a,b\",\"say \"\"hi\"\"\"\n,\n"},
+		{"#{ .header true }", "name,age\nIan,18\nJohn,21\n", "name,age\nIan,18\nJohn,21\n"},
+		{"#{ .delimiter \";\" }", "a;b\n\"x;y\";2\n", "a;b\n\"x;y\";2\n"},
+		{"#{ .header true .delimiter \"\t\" }", "name\tage\nIan\t18\n", "name\tage\nIan\t18\n"},
+		{"#{ .lazy-quotes true }", "a \"b\" c,d\n", "\"a \"\"b\"\" c\",d\n"},
+		{"#{ .lazy-quotes true }", "\"unclosed,e\n", "\"unclosed,e\n\"\n"},
+	}
+
+	for _, test := range tests {
+		rows, err1 := runCSV(test.text, test.options + " csv-read")
+
+		if err1 != nil || rows.getType() == objectTypeError {
+			t.Errorf("Unable to read %q with %s: %v", test.text, test.options, err1)
+			continue
+		}
+
+		written, err2 := runCSV(test.text, test.options + " csv-read " + test.options + " csv-write")
+
+		if err2 != nil || written.getType() != objectTypeString {
+			t.Errorf("Unable to write %q back with %s: %v", test.text, test.options, err2)
+			continue
+		}
+
+		if written.(*langObjectString).val != test.written {
+			t.Errorf("Expected %q to be written as %q with %s, but received %q", test.text, test.written, test.options, written.(*langObjectString).val)
+		}
+
+		/* The text written may quote differently, but without .lazy-quotes it must read the same */
+		options := test.options
+
+		if options == "#{ .lazy-quotes true }" {
+			options = "#{ }"
+		}
+
+		again, err3 := runCSV(written.(*langObjectString).val, options + " csv-read")
+
+		if err3 != nil || again.getType() == objectTypeError {
+			t.Errorf("Unable to read %q, written from %q: %v", written.(*langObjectString).val, test.text, err3)
+			continue
+		}
+
+		equal, err4 := rows.equals(again)
+
+		if err4 != nil || !equal {
+			t.Errorf("%q became %q after a round trip with %s", test.text, written.(*langObjectString).val, test.options)
+		}
+	}
+}
+
+/* Malformed CSV and rows that cannot be written give error values, and bad options are errors */
+func TestCSVFailures(t *testing.T) {
+	tests := []struct {
+		text string
+		code string
+		value bool
+	}{
+		{"a \"b\" c,d\n", "#{ } csv-read", true},
+		{"a,b\n1,2,3\n", "#{ } csv-read", true},
+		{"a;b\n", "#{ .delimiter \";;\" } csv-read", false},
+		{"a,b\n", "#{ .header 1 } csv-read", false},
+		{"a,b\n", "#{ .quote \"'\" } csv-read", false},
+		{"", "pop [[1 2]] #{ .delimiter \"\\\"\" } csv-write", true},
+		{"", "pop [[1 2]] #{ .header true } csv-write", false},
+	}
+
+	for _, test := range tests {
+		result, err := runCSV(test.text, test.code)
+
+		if test.value {
+			if err != nil || result.getType() != objectTypeError {
+				t.Errorf("Expected %q with %s to give an error value", test.text, test.code)
+			}
+		} else if err == nil {
+			t.Errorf("Expected %q with %s to fail", test.text, test.code)
+		}
+	}
+}
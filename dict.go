@@ -0,0 +1,349 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+/* Dictionaries */
+
+type dictKey struct {
+	typ langObjectType
+	name string
+	number float64
+	boolean bool
+}
+
+/*	Unlike lists, dictionaries are mutable. They are shared rather than copied, so a dictionary
+	stored in a variable and the same dictionary on the stack are one object. The keys and values
+	are kept in insertion order. */
+type langObjectDict struct {
+	index map[dictKey]int
+	keys []langObject
+	values []langObject
+}
+
+func newDict() *langObjectDict {
+	return &langObjectDict{make(map[dictKey]int), make([]langObject, 0), make([]langObject, 0),}
+}
+
+func toDictKey(obj langObject) (dictKey, error) {
+
+	switch obj.getType() {
+	case objectTypeString:
+		return dictKey{typ: objectTypeString, name: obj.(*langObjectString).val,}, nil
+	case objectTypeNumber:
+		return dictKey{typ: objectTypeNumber, number: obj.(*langObjectNumber).val,}, nil
+	case objectTypeBoolean:
+		return dictKey{typ: objectTypeBoolean, boolean: obj.(*langObjectBoolean).val,}, nil
+	case objectTypeSymbol:
+		return dictKey{typ: objectTypeSymbol, name: obj.(*langObjectSymbol).name,}, nil
+	}
+
+	return dictKey{}, errors.New("Only strings, numbers, booleans and symbols can be used as dictionary keys.")
+}
+
+func (l *langObjectDict) get(key langObject) (langObject, bool, error) {
+	k, err := toDictKey(key)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	i, ok := l.index[k]
+
+	if ok == false {
+		return nil, false, nil
+	}
+
+	return l.values[i], true, nil
+}
+
+func (l *langObjectDict) put(key langObject, value langObject) error {
+	k, err := toDictKey(key)
+
+	if err != nil {
+		return err
+	}
+
+	if i, ok := l.index[k]; ok {
+		l.values[i] = value
+		return nil
+	}
+
+	l.index[k] = len(l.keys)
+	l.keys = append(l.keys, key)
+	l.values = append(l.values, value)
+
+	return nil
+}
+
+func (l *langObjectDict) delete(key langObject) error {
+	k, err := toDictKey(key)
+
+	if err != nil {
+		return err
+	}
+
+	i, ok := l.index[k]
+
+	if ok == false {
+		return nil
+	}
+
+	delete(l.index, k)
+	l.keys = append(l.keys[:i], l.keys[i + 1:]...)
+	l.values = append(l.values[:i], l.values[i + 1:]...)
+
+	for k, j := range l.index {
+		if j > i {
+			l.index[k] = j - 1
+		}
+	}
+
+	return nil
+}
+
+func (l *langObjectDict) getType() langObjectType {
+	return objectTypeDict
+}
+
+func (l *langObjectDict) getValue() interface{} {
+	return l
+}
+
+func (l *langObjectDict) setValue(dict interface{}) {
+	*l = *(dict.(*langObjectDict))
+}
+
+/* Strings inside a dictionary are quoted, so that it prints the way it would be written */
+func literalString(obj langObject) string {
+	if obj.getType() == objectTypeString {
+		return strconv.Quote(obj.(*langObjectString).val)
+	}
+
+	return obj.toString()
+}
+
+func (l *langObjectDict) toString() string {
+	entries := make([]string, 0)
+
+	for i := range l.keys {
+		entries = append(entries, literalString(l.keys[i]) + " " + literalString(l.values[i]))
+	}
+
+	if len(entries) == 0 {
+		return "#{}"
+	}
+
+	return "#{ " + strings.Join(entries, " ") + " }"
+}
+
+func (l *langObjectDict) copy() langObject {
+	return l
+}
+
+/* Dictionaries are equal if they have the same keys, associated with equal values */
+func (l *langObjectDict) equals(o langObject) (bool, error) {
+	if o.getType() != objectTypeDict {
+		return false, nil
+	}
+
+	other := o.(*langObjectDict)
+
+	if len(l.keys) != len(other.keys) {
+		return false, nil
+	}
+
+	for i := range l.keys {
+		otherValue, ok, err := other.get(l.keys[i])
+
+		if err != nil || !ok {
+			return false, err
+		}
+
+		equal, eqErr := l.values[i].equals(otherValue)
+
+		if eqErr != nil || !equal {
+			return false, eqErr
+		}
+	}
+
+	return true, nil
+}
+
+func (l *langObjectDict) greaterThan(o langObject) (bool, error) {
+	return unordered(l, o, "greater than", "dictionary")
+}
+
+func (l *langObjectDict) lessThan(o langObject) (bool, error) {
+	return unordered(l, o, "less than", "dictionary")
+}
+
+func (s *stack) popDict() (*langObjectDict, error) {
+	obj, err := s.pop()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if obj.getType() != objectTypeDict {
+		return nil, errors.New("Expected, but did not receive a dictionary.")
+	}
+
+	return obj.(*langObjectDict), nil
+}
+
+func performDictOperation(typ operationType, s *stack, v *variableScope, st *symbolTable, in *interpreter) error {
+
+	switch typ {
+	case operationTypeDictEnd:
+		/* The literal's contents alternate between keys and values */
+		contents, err1 := s.collect()
+
+		if err1 != nil {
+			return err1
+		}
+
+		if len(contents) % 2 != 0 {
+			return errors.New("A dictionary literal must contain pairs of keys and values.")
+		}
+
+		dict := newDict()
+
+		for i := 0; i < len(contents); i += 2 {
+			err2 := dict.put(contents[i], contents[i + 1])
+
+			if err2 != nil {
+				return err2
+			}
+		}
+
+		s.push(dict)
+	case operationTypeDictPut:
+		value, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		key, err2 := s.pop()
+
+		if err2 != nil {
+			return err2
+		}
+
+		dict, err3 := s.popDict()
+
+		if err3 != nil {
+			return err3
+		}
+
+		err4 := dict.put(key, value)
+
+		if err4 != nil {
+			return err4
+		}
+
+		s.push(dict)
+	case operationTypeGet, operationTypeDictHas:
+		key, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		dict, err2 := s.popDict()
+
+		if err2 != nil {
+			return err2
+		}
+
+		value, ok, err3 := dict.get(key)
+
+		if err3 != nil {
+			return err3
+		}
+
+		if typ == operationTypeDictHas {
+			s.push(&langObjectBoolean{ok,})
+		} else if ok {
+			s.push(value)
+		} else {
+			/* A missing key is signalled with .None, like the lookups in examples/jsl-dict.jsl */
+			s.push(intern("None"))
+		}
+	case operationTypeDictDelete:
+		key, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		dict, err2 := s.popDict()
+
+		if err2 != nil {
+			return err2
+		}
+
+		err3 := dict.delete(key)
+
+		if err3 != nil {
+			return err3
+		}
+
+		s.push(dict)
+	case operationTypeDictKeys, operationTypeDictValues:
+		dict, err1 := s.popDict()
+
+		if err1 != nil {
+			return err1
+		}
+
+		if typ == operationTypeDictKeys {
+			s.push(newList(append([]langObject{}, dict.keys...)))
+		} else {
+			s.push(newList(append([]langObject{}, dict.values...)))
+		}
+	case operationTypeDictSize:
+		dict, err1 := s.popDict()
+
+		if err1 != nil {
+			return err1
+		}
+
+		s.push(&langObjectNumber{float64(len(dict.keys)),})
+	case operationTypeDictEach:
+		block, err1 := s.popCodeBlock()
+
+		if err1 != nil {
+			return err1
+		}
+
+		dict, err2 := s.popDict()
+
+		if err2 != nil {
+			return err2
+		}
+
+		/* Iterate over a snapshot, so that the block may modify the dictionary */
+		keys := append([]langObject{}, dict.keys...)
+		values := append([]langObject{}, dict.values...)
+
+		for i := range keys {
+			s.push(keys[i])
+			s.push(values[i])
+
+			err3 := block.call(s, st, in)
+
+			if err3 != nil {
+				return err3
+			}
+		}
+	default:
+		return errors.New("Invalid dictionary operation.")
+	}
+
+	return nil
+}
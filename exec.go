@@ -4,16 +4,59 @@ import (
 	"fmt"
 )
 
-func evalString(s string, programStack *stack, programVariableScope *variableScope, programSymbolTable *symbolTable, printStack bool) error {
-	_, items := lex("test", s)
+/* An error that happened in the code on a particular line of a file */
+type sourceError struct {
+	file string
+	line int
+	err error
+}
+
+func (e *sourceError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.file, e.line, e.err.Error())
+}
+
+/*	Adds a file and line to an error, unless the error already has them (from the code closest to
+	where it happened), or is not really an error but exit unwinding the program */
+func atLine(file string, line int, err error) error {
+	if _, ok := err.(*sourceError); ok || file == "" {
+		return err
+	}
+
+	if _, ok := err.(*exitError); ok {
+		return err
+	}
+
+	return &sourceError{file, line, err,}
+}
+
+/* Parses source code, which comes from the named file, or from the REPL if file is empty */
+func compile(s string, file string) (*langObjectCodeBlock, error) {
+	_, items := lex(file, s)
+
+	main, err := parseCodeBlock(&parser{items,file,0,})
+
+	if err != nil {
+		return nil, err
+	}
+
+	checkErr := verifyStackEffects(main)
+
+	if checkErr != nil {
+		return nil, checkErr
+	}
+
+	return main, nil
+}
 
-	main, err := parseCodeBlock(&parser{items, 0,})
+/* Evaluates source code, which comes from the named file, or from the REPL if file is empty */
+func evalString(s string, file string, programStack *stack, programVariableScope *variableScope, programSymbolTable *symbolTable, in *interpreter, printStack bool) error {
+	main, err := compile(s, file)
 
 	if err != nil {
 		return err
 	}
 
-	execErr := main.exec(programStack, programVariableScope, programSymbolTable, false)
+	execErr := main.exec(programStack, programVariableScope, programSymbolTable, in, false)
 
 	if execErr != nil {
 		return execErr
@@ -32,15 +75,6 @@ func printExecError(err error) {
 
 func handleIdentifier(v *variableScope, st *symbolTable, ident *langObjectIdentifier) (langObject, error) {
 
-	if ident.typ == identifierName {
-
-		return &langObjectIdentifier{
-			identifierReference,
-			ident.name,
-		}, nil
-
-	}
-
 	stKey, ok := v.get(ident.name)
 
 	switch ident.typ {
@@ -86,9 +120,19 @@ func handleIdentifier(v *variableScope, st *symbolTable, ident *langObjectIdenti
 	return nil, nil
 }
 
-func (l *langObjectCodeBlock) exec(s *stack, v *variableScope, st *symbolTable, cleanUpLocal bool) error {
+func (l *langObjectCodeBlock) errorAt(index int, err error) error {
+	if index >= len(l.lines) {
+		return err
+	}
+
+	return atLine(l.file, l.lines[index], err)
+}
+
+func (l *langObjectCodeBlock) exec(s *stack, v *variableScope, st *symbolTable, in *interpreter, cleanUpLocal bool) error {
 
-	for _, o := range l.code {
+	marks := len(s.marks)
+
+	for index, o := range l.code {
 
 		/*fmt.Println(o.toString())
 		fmt.Println("---")
@@ -96,14 +140,8 @@ func (l *langObjectCodeBlock) exec(s *stack, v *variableScope, st *symbolTable,
 		fmt.Println("---")*/
 
 		switch {
-		case o.getType() == objectTypeNumber|| o.getType() == objectTypeString || o.getType() == objectTypeBoolean:
+		case o.getType() == objectTypeNumber|| o.getType() == objectTypeString || o.getType() == objectTypeBoolean || o.getType() == objectTypeList || o.getType() == objectTypeSymbol:
 			s.push(o)
-		case o.getType() == objectTypeList:
-			if o.(*langObjectList).empty {
-				s.push(o)
-			} else {
-				s.push(o.copy())
-			}
 		case o.getType() == objectTypeCodeBlock:
 			codeBlock := o.(*langObjectCodeBlock)
 
@@ -116,7 +154,8 @@ func (l *langObjectCodeBlock) exec(s *stack, v *variableScope, st *symbolTable,
 			handleErr := o.(*langObjectCodeBlock).handleParentVariables(st)
 
 			if handleErr != nil {
-				return handleErr
+				s.unmark(marks)
+				return l.errorAt(index, handleErr)
 			}
 
 			s.push(o)
@@ -124,15 +163,17 @@ func (l *langObjectCodeBlock) exec(s *stack, v *variableScope, st *symbolTable,
 			identifier, identErr := handleIdentifier(v, st, o.(*langObjectIdentifier))
 
 			if identErr != nil {
-				return identErr
+				s.unmark(marks)
+				return l.errorAt(index, identErr)
 			}
 
 			s.push(identifier)
 		case o.getType() == objectTypeOperation:
-			err := performOperation(o.getValue().(operationType), s, v, st)
+			err := performOperation(o.getValue().(operationType), s, v, st, in)
 
 			if err != nil {
-				return err
+				s.unmark(marks)
+				return l.errorAt(index, err)
 			}
 		}
 
@@ -155,4 +196,47 @@ func (l *langObjectCodeBlock) exec(s *stack, v *variableScope, st *symbolTable,
 
 	return nil
 
-} 
\ No newline at end of file
+}
+
+/*	Calls a code block the way ! does, in a new scope whose parent is the block's parent scope. If
+	the block declares its stack effect, the stack is checked against it before and after the call. */
+func (l *langObjectCodeBlock) call(s *stack, st *symbolTable, in *interpreter) error {
+	depth := len(s.contents)
+
+	if l.effect != nil && depth < len(l.effect.inputs) {
+		return fmt.Errorf("Code block %s expects %d items on the stack, but there are only %d.", l.effect.toString(), len(l.effect.inputs), depth)
+	}
+
+	if depth < len(l.params) {
+		return fmt.Errorf("Code block %s expects %d items on the stack, but there are only %d.", l.paramsString(), len(l.params), depth)
+	}
+
+	newScope := &variableScope{make(map[string]*langVariable),l.parentScope,}
+
+	/* Parameters are bound in the order they are declared, so the last one takes the top of the stack */
+	for i := len(l.params) - 1; i >= 0; i-- {
+		val, _ := s.pop()
+
+		paramErr := newScope.bindParameter(l.params[i], val, st)
+
+		if paramErr != nil {
+			return paramErr
+		}
+	}
+
+	err := l.exec(s, newScope, st, in, true)
+
+	if err != nil {
+		return err
+	}
+
+	if l.effect != nil {
+		expected := len(l.effect.outputs) - len(l.effect.inputs)
+
+		if actual := len(s.contents) - depth; actual != expected {
+			return fmt.Errorf("Code block %s should change the stack depth by %+d, but changed it by %+d.", l.effect.toString(), expected, actual)
+		}
+	}
+
+	return nil
+}
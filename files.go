@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+/* File System Operations */
+
+/*	Operations on files push an error value rather than stopping the program when they fail, so
+	that programs can check for failure with error?. Operations that have nothing else to return
+	push true when they succeed. */
+
+/* Pushes the result of an operation: an error value if err is set, or result otherwise */
+func (s *stack) pushResult(result langObject, err error) {
+	if err != nil {
+		s.push(&langObjectError{err.Error(),})
+	} else {
+		s.push(result)
+	}
+}
+
+func stringList(strs []string) *langObjectList {
+	elements := make([]langObject, 0)
+
+	for _, str := range strs {
+		elements = append(elements, &langObjectString{str,})
+	}
+
+	return newList(elements)
+}
+
+func performFileOperation(typ operationType, s *stack, v *variableScope, st *symbolTable, in *interpreter) error {
+
+	if !in.allows(capabilityFiles) {
+		return errors.New("File system access is not allowed.")
+	}
+
+	path, err1 := s.popString("a path")
+
+	if err1 != nil {
+		return err1
+	}
+
+	switch typ {
+	case operationTypeReadFile:
+		contents, err2 := os.ReadFile(path)
+		s.pushResult(&langObjectString{string(contents),}, err2)
+	case operationTypeWriteFile, operationTypeAppendFile:
+		contents, err2 := s.popString("a string to write")
+
+		if err2 != nil {
+			return err2
+		}
+
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+
+		if typ == operationTypeAppendFile {
+			flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		}
+
+		file, err3 := os.OpenFile(path, flags, 0644)
+
+		if err3 != nil {
+			s.pushResult(nil, err3)
+			return nil
+		}
+
+		_, err4 := file.WriteString(contents)
+		err5 := file.Close()
+
+		if err4 == nil {
+			err4 = err5
+		}
+
+		s.pushResult(&langObjectBoolean{true,}, err4)
+	case operationTypeListDir:
+		entries, err2 := os.ReadDir(path)
+
+		names := make([]string, 0)
+
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+
+		s.pushResult(stringList(names), err2)
+	case operationTypeExists:
+		_, err2 := os.Stat(path)
+		s.push(&langObjectBoolean{err2 == nil,})
+	case operationTypeStat:
+		info, err2 := os.Stat(path)
+
+		if err2 != nil {
+			s.pushResult(nil, err2)
+			return nil
+		}
+
+		dict := newDict()
+		dict.put(intern("name"), &langObjectString{info.Name(),})
+		dict.put(intern("size"), &langObjectNumber{float64(info.Size()),})
+		dict.put(intern("mtime"), &langObjectNumber{float64(info.ModTime().Unix()),})
+		dict.put(intern("dir"), &langObjectBoolean{info.IsDir(),})
+		dict.put(intern("mode"), &langObjectString{info.Mode().String(),})
+
+		s.push(dict)
+	case operationTypeMakeDir:
+		s.pushResult(&langObjectBoolean{true,}, os.MkdirAll(path, 0755))
+	case operationTypeRemove:
+		s.pushResult(&langObjectBoolean{true,}, os.Remove(path))
+	case operationTypeGlob:
+		matches, err2 := filepath.Glob(path)
+		sort.Strings(matches)
+		s.pushResult(stringList(matches), err2)
+	default:
+		return errors.New("Invalid file operation.")
+	}
+
+	return nil
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*	A format string is made up of literal text and directives modelled on those of Go's fmt
+	package:
+
+		%[flags][[index]][width][.precision]verb
+
+	Directives consume their arguments in order, unless an explicit one-based index is
+	given, in which case later directives continue from the argument after it. */
+
+type formatDirective struct {
+	literal string
+	flags string
+	width string
+	precision string
+	verb rune
+	argument int
+}
+
+const formatFlagRunes = "+-# 0"
+const formatVerbRunes = "vsqdfFeEgGxXt"
+
+func parseFormatString(format string) ([]formatDirective, int, error) {
+
+	directives := make([]formatDirective, 0)
+	literal := ""
+	argNum := 0
+	argCount := 0
+
+	runes := []rune(format)
+
+	for i := 0; i < len(runes); i++ {
+
+		if runes[i] != '%' {
+			literal += string(runes[i])
+			continue
+		}
+
+		i++
+
+		if i < len(runes) && runes[i] == '%' {
+			literal += "%"
+			continue
+		}
+
+		if literal != "" {
+			directives = append(directives, formatDirective{literal: literal, argument: -1,})
+			literal = ""
+		}
+
+		directive := formatDirective{}
+
+		for ; i < len(runes) && strings.IndexRune(formatFlagRunes, runes[i]) >= 0; i++ {
+			directive.flags += string(runes[i])
+		}
+
+		if i < len(runes) && runes[i] == '[' {
+			end := i + 1
+
+			for ; end < len(runes) && runes[end] != ']'; end++ {
+			}
+
+			if end >= len(runes) {
+				return nil, 0, errors.New("Unterminated argument index in format string.")
+			}
+
+			index, err := strconv.Atoi(string(runes[i + 1:end]))
+
+			if err != nil || index < 1 {
+				return nil, 0, fmt.Errorf("Bad argument index '%s' in format string.", string(runes[i + 1:end]))
+			}
+
+			argNum = index - 1
+			i = end + 1
+		}
+
+		for ; i < len(runes) && '0' <= runes[i] && runes[i] <= '9'; i++ {
+			directive.width += string(runes[i])
+		}
+
+		if i < len(runes) && runes[i] == '.' {
+			directive.precision = "."
+
+			for i++; i < len(runes) && '0' <= runes[i] && runes[i] <= '9'; i++ {
+				directive.precision += string(runes[i])
+			}
+		}
+
+		if i >= len(runes) {
+			return nil, 0, errors.New("Format string ends in the middle of a directive.")
+		}
+
+		if strings.IndexRune(formatVerbRunes, runes[i]) < 0 {
+			return nil, 0, fmt.Errorf("Unknown format verb '%%%c'.", runes[i])
+		}
+
+		directive.verb = runes[i]
+		directive.argument = argNum
+
+		directives = append(directives, directive)
+
+		argNum++
+
+		if argNum > argCount {
+			argCount = argNum
+		}
+	}
+
+	if literal != "" {
+		directives = append(directives, formatDirective{literal: literal, argument: -1,})
+	}
+
+	return directives, argCount, nil
+}
+
+/* Numbers are shown without trailing zeros by %v and %s, so 18 formats as "18" and not "18.000000" */
+func formatNaturalString(obj langObject) string {
+	if obj.getType() == objectTypeNumber {
+		return strconv.FormatFloat(obj.(*langObjectNumber).val, 'f', -1, 64)
+	}
+
+	return obj.toString()
+}
+
+func (d *formatDirective) apply(obj langObject) (string, error) {
+
+	goFormat := "%" + d.flags + d.width + d.precision
+
+	switch d.verb {
+	case 'v', 's':
+		return fmt.Sprintf(goFormat + "s", formatNaturalString(obj)), nil
+	case 'q':
+		return fmt.Sprintf(goFormat + "q", formatNaturalString(obj)), nil
+	case 'd':
+		if obj.getType() != objectTypeNumber {
+			return "", fmt.Errorf("Format verb '%%d' expects a number, but received '%s'.", obj.toString())
+		}
+
+		return fmt.Sprintf(goFormat + "d", int64(obj.(*langObjectNumber).val)), nil
+	case 'f', 'F', 'e', 'E', 'g', 'G':
+		if obj.getType() != objectTypeNumber {
+			return "", fmt.Errorf("Format verb '%%%c' expects a number, but received '%s'.", d.verb, obj.toString())
+		}
+
+		return fmt.Sprintf(goFormat + string(d.verb), obj.(*langObjectNumber).val), nil
+	case 'x', 'X':
+		switch obj.getType() {
+		case objectTypeNumber:
+			return fmt.Sprintf(goFormat + string(d.verb), int64(obj.(*langObjectNumber).val)), nil
+		case objectTypeString:
+			return fmt.Sprintf(goFormat + string(d.verb), obj.(*langObjectString).val), nil
+		default:
+			return "", fmt.Errorf("Format verb '%%%c' expects a number or a string, but received '%s'.", d.verb, obj.toString())
+		}
+	case 't':
+		if obj.getType() != objectTypeBoolean {
+			return "", fmt.Errorf("Format verb '%%t' expects a boolean, but received '%s'.", obj.toString())
+		}
+
+		return fmt.Sprintf(goFormat + "t", obj.(*langObjectBoolean).val), nil
+	}
+
+	return "", fmt.Errorf("Unknown format verb '%%%c'.", d.verb)
+}
+
+/*	format takes the format string from the top of the stack. If the string has directives and the
+	object beneath it is a list, the arguments are taken from the list, head first. Otherwise, as
+	many arguments as the format string requires are popped from the stack, the deepest being the
+	first argument. A format string without directives takes nothing from beneath it. */
+func performFormat(s *stack, v *variableScope, st *symbolTable) error {
+
+	template, err1 := s.popString("a format string")
+
+	if err1 != nil {
+		return err1
+	}
+
+	directives, argCount, err2 := parseFormatString(template)
+
+	if err2 != nil {
+		return err2
+	}
+
+	var args []langObject
+
+	top, topErr := s.peek()
+
+	if argCount > 0 && topErr == nil && top.getType() == objectTypeList {
+		s.pop()
+
+		args = top.(*langObjectList).elements()
+
+		if len(args) < argCount {
+			return fmt.Errorf("Format string requires %d arguments, but the list contains %d.", argCount, len(args))
+		}
+	} else {
+		args = make([]langObject, argCount)
+
+		for i := argCount - 1; i >= 0; i-- {
+			arg, err3 := s.pop()
+
+			if err3 != nil {
+				return err3
+			}
+
+			/* Garbage collection */
+			if arg.getType() == objectTypeReference {
+				err4 := st.decReference(arg.(*langObjectReference).key)
+
+				if err4 != nil {
+					return err4
+				}
+			}
+
+			args[i] = arg
+		}
+	}
+
+	result := ""
+
+	for _, directive := range directives {
+
+		if directive.argument < 0 {
+			result += directive.literal
+			continue
+		}
+
+		formatted, err5 := directive.apply(args[directive.argument])
+
+		if err5 != nil {
+			return err5
+		}
+
+		result += formatted
+	}
+
+	return s.push(&langObjectString{result,})
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+/* The Host */
+
+/*	Everything a program can learn about or do to the world outside the interpreter, other than
+	its input, output and files, goes through the host. A program embedding the interpreter can
+	replace it, for example to fix the time or to stop exit from ending the process. */
+type host interface {
+	getenv(name string) (string, bool)
+	setenv(name string, value string) error
+	now() time.Time
+	sleep(d time.Duration)
+	exit(code int)
+	args() []string
+}
+
+/* The host used by jsl itself, which is the real operating system */
+type osHost struct {
+	arguments []string
+}
+
+func (h *osHost) getenv(name string) (string, bool) {
+	return os.LookupEnv(name)
+}
+
+func (h *osHost) setenv(name string, value string) error {
+	return os.Setenv(name, value)
+}
+
+func (h *osHost) now() time.Time {
+	return time.Now()
+}
+
+func (h *osHost) sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (h *osHost) exit(code int) {
+	os.Exit(code)
+}
+
+func (h *osHost) args() []string {
+	return h.arguments
+}
+
+/*	Returned by exit, so that the program stops wherever it is, however deeply it is nested. Whatever
+	is running the program then hands the status to the host. */
+type exitError struct {
+	status int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("Exit with status %d.", e.status)
+}
+
+/* If err came from exit, hands its status to the host, and returns it */
+func (in *interpreter) exitOn(err error) (int, bool) {
+	exit, ok := err.(*exitError)
+
+	if !ok {
+		return 0, false
+	}
+
+	in.host.exit(exit.status)
+
+	return exit.status, true
+}
+
+/* Times are numbers of seconds since 1970, which may have a fractional part */
+func timeToNumber(t time.Time) *langObjectNumber {
+	return &langObjectNumber{float64(t.UnixNano()) / float64(time.Second),}
+}
+
+func numberToTime(n float64) time.Time {
+	seconds, fraction := math.Modf(n)
+
+	return time.Unix(int64(seconds), int64(fraction * float64(time.Second)))
+}
+
+func performHostOperation(typ operationType, s *stack, v *variableScope, st *symbolTable, in *interpreter) error {
+
+	switch typ {
+	case operationTypeGetenv:
+		name, err1 := s.popString("the name of an environment variable")
+
+		if err1 != nil {
+			return err1
+		}
+
+		if value, ok := in.host.getenv(name); ok {
+			s.push(&langObjectString{value,})
+		} else {
+			s.push(intern("None"))
+		}
+	case operationTypeSetenv:
+		/* value name -- */
+		name, err1 := s.popString("the name of an environment variable")
+
+		if err1 != nil {
+			return err1
+		}
+
+		value, err2 := s.popString("a value for the environment variable")
+
+		if err2 != nil {
+			return err2
+		}
+
+		err3 := in.host.setenv(name, value)
+
+		if err3 != nil {
+			return err3
+		}
+	case operationTypeNow:
+		s.push(timeToNumber(in.host.now()))
+	case operationTypeTimeFormat:
+		/* time layout -- string, where the layout is written in the manner of Go's time package */
+		layout, err1 := s.popString("a time layout")
+
+		if err1 != nil {
+			return err1
+		}
+
+		t, err2 := s.popNumber()
+
+		if err2 != nil {
+			return err2
+		}
+
+		s.push(&langObjectString{numberToTime(t).Format(layout),})
+	case operationTypeTimeParse:
+		/* string layout -- time */
+		layout, err1 := s.popString("a time layout")
+
+		if err1 != nil {
+			return err1
+		}
+
+		str, err2 := s.popString("a time to parse")
+
+		if err2 != nil {
+			return err2
+		}
+
+		/* Like string->number, a time that cannot be parsed gives an error value */
+		t, err3 := time.ParseInLocation(layout, str, time.Local)
+
+		if err3 != nil {
+			s.pushResult(nil, err3)
+		} else {
+			s.push(timeToNumber(t))
+		}
+	case operationTypeSleep:
+		seconds, err1 := s.popNumber()
+
+		if err1 != nil {
+			return err1
+		}
+
+		/* 2^63 is exact as a float, so any smaller duration converts without overflowing */
+		if nanoseconds := seconds * float64(time.Second); nanoseconds >= float64(math.MaxInt64) {
+			return fmt.Errorf("Unable to sleep for %v seconds, which is too long.", seconds)
+		} else if nanoseconds > 0 {
+			in.host.sleep(time.Duration(nanoseconds))
+		}
+	case operationTypeExit:
+		n, err1 := s.popNumber()
+
+		if err1 != nil {
+			return err1
+		}
+
+		status, err2 := wholeNumberIn(n, 0, 255, "the exit status")
+
+		if err2 != nil {
+			return err2
+		}
+
+		return &exitError{int(status),}
+	case operationTypeArgs:
+		s.push(stringList(in.host.args()))
+	default:
+		return errors.New("Invalid host operation.")
+	}
+
+	return nil
+}
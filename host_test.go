@@ -0,0 +1,184 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+/* A host that keeps everything to itself, so that tests do not depend on the clock or end the process */
+type fakeHost struct {
+	env map[string]string
+	time time.Time
+	arguments []string
+	sleeps []time.Duration
+	exits []int
+}
+
+func newFakeHost() *fakeHost {
+	return &fakeHost{make(map[string]string), time.Date(2024, time.October, 19, 14, 30, 0, 0, time.Local), []string{"a", "b c"}, make([]time.Duration, 0), make([]int, 0),}
+}
+
+func (h *fakeHost) getenv(name string) (string, bool) {
+	value, ok := h.env[name]
+	return value, ok
+}
+
+func (h *fakeHost) setenv(name string, value string) error {
+	h.env[name] = value
+	return nil
+}
+
+func (h *fakeHost) now() time.Time {
+	return h.time
+}
+
+func (h *fakeHost) sleep(d time.Duration) {
+	h.sleeps = append(h.sleeps, d)
+}
+
+func (h *fakeHost) exit(code int) {
+	h.exits = append(h.exits, code)
+}
+
+func (h *fakeHost) args() []string {
+	return h.arguments
+}
+
+/* Runs code with a fake host, and returns the host and what the code left on the stack */
+func runWithFakeHost(t *testing.T, code string) (*fakeHost, []langObject) {
+	s, v, st, in := newTestProgram()
+	host := newFakeHost()
+	in.host = host
+
+	err := evalString(code, "", s, v, st, in, false)
+
+	if err != nil {
+		t.Fatalf("Unable to run %s: %s", code, err)
+	}
+
+	return host, s.contents
+}
+
+func expectStrings(t *testing.T, code string, results []langObject, expected ...string) {
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %s to leave %d items, but it left %d", code, len(expected), len(results))
+	}
+
+	for i := range expected {
+		if results[i].toString() != expected[i] {
+			t.Errorf("Expected %s to leave %q, but it left %q", code, expected[i], results[i].toString())
+		}
+	}
+}
+
+func TestNow(t *testing.T) {
+	host, results := runWithFakeHost(t, "now")
+
+	expectStrings(t, "now", results, timeToNumber(host.time).toString())
+}
+
+func TestTimeFormat(t *testing.T) {
+	code := `now "2006-01-02 15:04:05 Monday" time-format`
+	_, results := runWithFakeHost(t, code)
+
+	expectStrings(t, code, results, "2024-10-19 14:30:00 Saturday")
+}
+
+func TestTimeRoundTrip(t *testing.T) {
+	layouts := []string{"2006-01-02 15:04:05", "02/01/2006 3:04PM", time.RFC3339}
+
+	for _, layout := range layouts {
+		code := `now "` + layout + `" time-format "` + layout + `" time-parse now =`
+		_, results := runWithFakeHost(t, code)
+
+		expectStrings(t, code, results, "true")
+	}
+
+	/* A time that does not match the layout gives an error value */
+	code := `"yesterday" "2006-01-02" time-parse error?`
+	_, results := runWithFakeHost(t, code)
+
+	expectStrings(t, code, results, "true")
+}
+
+func TestSleep(t *testing.T) {
+	host, _ := runWithFakeHost(t, "1.5 sleep 0 sleep -1 sleep")
+
+	if len(host.sleeps) != 1 || host.sleeps[0] != 1500 * time.Millisecond {
+		t.Errorf("Expected one sleep of 1.5s, but slept %v", host.sleeps)
+	}
+
+	/* Too long to be a time.Duration */
+	s, v, st, in := newTestProgram()
+	in.host = newFakeHost()
+	s.push(&langObjectNumber{1e10,})
+
+	if err := performHostOperation(operationTypeSleep, s, v, st, in); err == nil {
+		t.Errorf("Expected sleeping for 1e10 seconds to fail")
+	}
+}
+
+func TestExit(t *testing.T) {
+	s, v, st, in := newTestProgram()
+	host := newFakeHost()
+	in.host = host
+
+	/* exit unwinds out of the block, and nothing after it runs */
+	err := evalString("1 { 3 exit 2 } ! 4", "", s, v, st, in, false)
+
+	if status, ok := in.exitOn(err); !ok || status != 3 {
+		t.Fatalf("Expected exit with status 3, but received %v", err)
+	}
+
+	if len(host.exits) != 1 || host.exits[0] != 3 {
+		t.Errorf("Expected the host to be given status 3, but it was given %v", host.exits)
+	}
+
+	expectStrings(t, "1 { 3 exit 2 } ! 4", s.contents, "1.000000")
+
+	for _, code := range []string{"1.5 exit", "-1 exit", "256 exit"} {
+		err := evalString(code, "", s, v, st, in, false)
+
+		if _, ok := err.(*exitError); ok || err == nil {
+			t.Errorf("Expected %s to fail", code)
+		}
+	}
+}
+
+/* exit on one line of input stops the rest from being read, and gives the exit status */
+func TestExitProcessingLines(t *testing.T) {
+	output := &strings.Builder{}
+	s, v, st, _ := newTestProgram()
+	in := newInterpreterWithIO(strings.NewReader("a\nb\nc\n"), output)
+	host := newFakeHost()
+	in.host = host
+
+	status := processLines(`{ 7 exit } over "b" = if`, "", "", true, s, v, st, in)
+
+	if status != 7 || len(host.exits) != 1 || host.exits[0] != 7 {
+		t.Errorf("Expected exit with status 7, but received %d after exits %v", status, host.exits)
+	}
+
+	if output.String() != "a\n" {
+		t.Errorf("Expected only the first line to be printed, but received %q", output.String())
+	}
+}
+
+func TestArgs(t *testing.T) {
+	code := "args list->stack args length"
+	_, results := runWithFakeHost(t, code)
+
+	expectStrings(t, code, results, "a", "b c", "2.000000")
+}
+
+func TestEnvironment(t *testing.T) {
+	code := `"HOME" getenv "v" "X" setenv "X" getenv`
+	host, results := runWithFakeHost(t, code)
+
+	expectStrings(t, code, results, ".None", "v")
+
+	if host.env["X"] != "v" {
+		t.Errorf("Expected setenv to set X on the host")
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+)
+
+/* Things a program may be allowed to do, which a sandbox can deny */
+type capability int
+
+const (
+	capabilityFiles capability = 1 << iota
+	capabilityProcess
+)
+
+const capabilityAll = capabilityFiles | capabilityProcess
+
+/*	The state of a running program that is not part of its stack, variables or symbol table: what it
+	is allowed to do, where its input comes from and its output goes, the files it is in the middle
+	of evaluating, the modules it has imported and where to find more of them, the regular
+	expressions it has compiled, the host it runs on, and its source of random numbers. */
+type interpreter struct {
+	capabilities capability
+	input *bufio.Reader
+	output io.Writer
+	files []string
+	included map[string]bool
+	modules map[string]*module
+	loading []*module
+	searchPath []string
+	regexps *regexpCache
+	host host
+	random *rand.Rand
+}
+
+/* Creates an interpreter that reads from standard input and writes to standard output */
+func newInterpreter() *interpreter {
+	return newInterpreterWithIO(os.Stdin, os.Stdout)
+}
+
+func newInterpreterWithIO(input io.Reader, output io.Writer) *interpreter {
+	searchPath := make([]string, 0)
+
+	for _, dir := range filepath.SplitList(os.Getenv("JSL_PATH")) {
+		if dir != "" {
+			searchPath = append(searchPath, dir)
+		}
+	}
+
+	return &interpreter{capabilityAll, bufio.NewReader(input), output, make([]string, 0), make(map[string]bool), make(map[string]*module), make([]*module, 0), searchPath, newRegexpCache(), &osHost{make([]string, 0),}, defaultRandom(),}
+}
+
+func (in *interpreter) allows(c capability) bool {
+	return in.capabilities & c != 0
+}
+
+/* Takes away a capability, so that operations needing it fail */
+func (in *interpreter) deny(c capability) {
+	in.capabilities &^= c
+}
+
+/* The module that is being loaded, or nil at the top level of a program */
+func (in *interpreter) currentModule() *module {
+	if len(in.loading) == 0 {
+		return nil
+	}
+
+	return in.loading[len(in.loading) - 1]
+}
+
+/* The file being evaluated, or "" for code typed into the REPL */
+func (in *interpreter) currentFile() string {
+	if len(in.files) == 0 {
+		return ""
+	}
+
+	return in.files[len(in.files) - 1]
+}
+
+/* Resolves a path relative to the directory of the file being evaluated, rather than the working directory */
+func (in *interpreter) resolvePath(path string) string {
+	if filepath.IsAbs(path) || in.currentFile() == "" {
+		return path
+	}
+
+	return filepath.Join(filepath.Dir(in.currentFile()), path)
+}
+
+/* Evaluates a source file, keeping track of it so that relative paths and errors refer to it */
+func (in *interpreter) evalFile(path string, s *stack, v *variableScope, st *symbolTable) error {
+
+	contents, err1 := os.ReadFile(path)
+
+	if err1 != nil {
+		return err1
+	}
+
+	in.files = append(in.files, path)
+
+	err2 := evalString(string(contents), path, s, v, st, in, false)
+
+	in.files = in.files[:len(in.files) - 1]
+
+	return err2
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/* Console I/O */
+
+/*	Reads a line of input, without its line ending. At the end of the input, ok is false. A last
+	line with no line ending is still returned. */
+func (in *interpreter) readLine() (line string, ok bool, err error) {
+	line, err = in.input.ReadString('\n')
+
+	if err == io.EOF {
+		if line == "" {
+			return "", false, nil
+		}
+
+		err = nil
+	}
+
+	return strings.TrimRight(line, "\r\n"), true, err
+}
+
+func performIOOperation(typ operationType, s *stack, v *variableScope, st *symbolTable, in *interpreter) error {
+
+	switch typ {
+	case operationTypePrint, operationTypePrintLine:
+		obj, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		err2 := release(obj, st)
+
+		if err2 != nil {
+			return err2
+		}
+
+		text := obj.toString()
+
+		if typ == operationTypePrintLine {
+			text += "\n"
+		}
+
+		_, err3 := io.WriteString(in.output, text)
+
+		if err3 != nil {
+			return err3
+		}
+	case operationTypeEmit:
+		text, err1 := s.popString("a string")
+
+		if err1 != nil {
+			return err1
+		}
+
+		_, err2 := io.WriteString(in.output, text)
+
+		if err2 != nil {
+			return err2
+		}
+	case operationTypeReadLine:
+		line, ok, err1 := in.readLine()
+
+		if err1 != nil {
+			return err1
+		}
+
+		if ok == false {
+			s.push(intern("eof"))
+		} else {
+			s.push(&langObjectString{line,})
+		}
+	case operationTypeReadAll:
+		contents, err1 := io.ReadAll(in.input)
+
+		if err1 != nil {
+			return fmt.Errorf("Unable to read input: %s", err1.Error())
+		}
+
+		s.push(&langObjectString{string(contents),})
+	default:
+		return errors.New("Invalid I/O operation.")
+	}
+
+	return nil
+}
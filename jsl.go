@@ -1,42 +1,111 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
-	"bufio"
 )
 
 func main() {
 
-	fmt.Println("JSL")
-	fmt.Println()
+	sandbox := flag.Bool("sandbox", false, "deny programs access to the file system and to other programs")
+	eachLine := flag.Bool("n", false, "run the code given as an argument once for each line of input")
+	printLines := flag.Bool("p", false, "like -n, but print the top of the stack after each line")
+	begin := flag.String("begin", "", "with -n or -p, code to run before the first line (and any BEGIN blocks)")
+	end := flag.String("end", "", "with -n or -p, code to run after the last line (and any END blocks)")
 
-	scanner := bufio.NewScanner(os.Stdin)
+	flag.Parse()
+
+	if flag.Arg(0) == "check" {
+		os.Exit(checkFiles(flag.Args()[1:]))
+	}
 
-	programStack := &stack{make([]langObject, 0)}
-	programSymbolTable := &symbolTable{make(map[uint64]*symbolTableEntry),}
+	programStack := &stack{make([]langObject, 0),nil,}
+	programSymbolTable := &symbolTable{make(map[uint64]*symbolTableEntry),nil,}
 	programVariableScope := &variableScope{make(map[string]*langVariable),nil,}
+	programInterpreter := newInterpreter()
+
+	if *sandbox {
+		programInterpreter.deny(capabilityFiles | capabilityProcess)
+	}
+
+	/* Any arguments after the options are for the program, except the code given to -n */
+	programInterpreter.host = &osHost{flag.Args(),}
+
+	if *eachLine || *printLines {
+		if flag.NArg() < 1 {
+			fmt.Fprintln(os.Stderr, "Usage: jsl -n [-p] [-begin code] [-end code] code [argument ...]")
+			os.Exit(2)
+		}
+
+		programInterpreter.host = &osHost{flag.Args()[1:],}
+
+		os.Exit(processLines(flag.Arg(0), *begin, *end, *printLines, programStack, programVariableScope, programSymbolTable, programInterpreter))
+	}
+
+	fmt.Println("JSL")
+	fmt.Println()
 
 	for true {
 
 		fmt.Print("> ")
 
-		if !scanner.Scan() {
+		/* The REPL shares its input with read-line, so that programs can read the lines after them */
+		input, ok, readErr := programInterpreter.readLine()
+
+		if !ok || readErr != nil {
 			fmt.Print("\n")
 			os.Exit(0)
 		}
 
-		input := scanner.Text()
-
 		if input == "exit" || input == "quit" {
 			os.Exit(0)
 		}
 
-		err := evalString(input, programStack, programVariableScope, programSymbolTable, true)
+		err := evalString(input, "", programStack, programVariableScope, programSymbolTable, programInterpreter, true)
 
 		if err != nil {
-			printExecError(err)
+			if _, exited := programInterpreter.exitOn(err); !exited {
+				printExecError(err)
+			}
 		}		
 		
 	}
-}
\ No newline at end of file
+}
+/* Checks each file without running it, printing any problems found. Returns the exit status. */
+func checkFiles(files []string) int {
+
+	if len(files) == 0 {
+		fmt.Println("Usage: jsl check file.jsl ...")
+		return 2
+	}
+
+	status := 0
+
+	for _, file := range files {
+		contents, err1 := os.ReadFile(file)
+
+		if err1 != nil {
+			fmt.Printf("%s: %s\n", file, err1.Error())
+			status = 1
+			continue
+		}
+
+		_, items := lex(file, string(contents))
+
+		program, err2 := parseCodeBlock(&parser{items,file,0,})
+
+		if err2 != nil {
+			fmt.Printf("%s: %s\n", file, err2.Error())
+			status = 1
+			continue
+		}
+
+		for _, d := range checkProgram(program) {
+			fmt.Printf("%s:%d: %s\n", file, d.line, d.toString())
+			status = 1
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/* JSON */
+
+/*	JSON arrays become lists, objects become dictionaries with string keys (in the order they were
+	written), and null becomes the symbol .None. Going the other way, arrays and lists both become
+	JSON arrays, and records become objects. */
+
+/* The most spaces json-pretty will indent by */
+const maxJSONIndent = 16
+
+func decodeJSON(dec *json.Decoder) (langObject, error) {
+	token, err := dec.Token()
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '[':
+			elements := make([]langObject, 0)
+
+			for dec.More() {
+				element, elementErr := decodeJSON(dec)
+
+				if elementErr != nil {
+					return nil, elementErr
+				}
+
+				elements = append(elements, element)
+			}
+
+			_, closeErr := dec.Token()
+
+			return newList(elements), closeErr
+		case '{':
+			dict := newDict()
+
+			for dec.More() {
+				key, keyErr := dec.Token()
+
+				if keyErr != nil {
+					return nil, keyErr
+				}
+
+				value, valueErr := decodeJSON(dec)
+
+				if valueErr != nil {
+					return nil, valueErr
+				}
+
+				dict.put(&langObjectString{key.(string),}, value)
+			}
+
+			_, closeErr := dec.Token()
+
+			return dict, closeErr
+		}
+	case string:
+		return &langObjectString{t,}, nil
+	case float64:
+		return &langObjectNumber{t,}, nil
+	case bool:
+		return &langObjectBoolean{t,}, nil
+	case nil:
+		return intern("None"), nil
+	}
+
+	return nil, fmt.Errorf("Unexpected JSON token %v.", token)
+}
+
+func parseJSON(text string) (langObject, error) {
+	dec := json.NewDecoder(strings.NewReader(text))
+
+	value, err := decodeJSON(dec)
+
+	if err != nil {
+		return nil, err
+	}
+
+	/* There should be nothing after the value */
+	if _, trailingErr := dec.Token(); trailingErr != io.EOF {
+		return nil, errors.New("Unexpected text after the JSON value.")
+	}
+
+	return value, nil
+}
+
+func writeJSONString(b *strings.Builder, str string) {
+	encoded, _ := json.Marshal(str)
+	b.Write(encoded)
+}
+
+/* Writes a JSON key: strings as they are, and symbols, numbers and booleans as their names */
+func writeJSONKey(b *strings.Builder, key langObject) {
+	switch key.getType() {
+	case objectTypeString:
+		writeJSONString(b, key.(*langObjectString).val)
+	case objectTypeSymbol:
+		writeJSONString(b, key.(*langObjectSymbol).name)
+	case objectTypeNumber:
+		writeJSONString(b, strconv.FormatFloat(key.(*langObjectNumber).val, 'f', -1, 64))
+	default:
+		writeJSONString(b, key.toString())
+	}
+}
+
+func writeJSONArray(b *strings.Builder, elements []langObject) error {
+	b.WriteString("[")
+
+	for i, element := range elements {
+		if i > 0 {
+			b.WriteString(",")
+		}
+
+		err := writeJSON(b, element)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	b.WriteString("]")
+
+	return nil
+}
+
+func writeJSONObject(b *strings.Builder, keys []langObject, values []langObject) error {
+	b.WriteString("{")
+
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteString(",")
+		}
+
+		writeJSONKey(b, key)
+		b.WriteString(":")
+
+		err := writeJSON(b, values[i])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	b.WriteString("}")
+
+	return nil
+}
+
+func writeJSON(b *strings.Builder, obj langObject) error {
+
+	switch obj.getType() {
+	case objectTypeString:
+		writeJSONString(b, obj.(*langObjectString).val)
+	case objectTypeNumber:
+		n := obj.(*langObjectNumber).val
+
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return fmt.Errorf("%v cannot be written as JSON.", n)
+		}
+
+		b.WriteString(strconv.FormatFloat(n, 'f', -1, 64))
+	case objectTypeBoolean:
+		b.WriteString(strconv.FormatBool(obj.(*langObjectBoolean).val))
+	case objectTypeSymbol:
+		if obj == intern("None") {
+			b.WriteString("null")
+		} else {
+			writeJSONString(b, obj.(*langObjectSymbol).name)
+		}
+	case objectTypeList:
+		return writeJSONArray(b, obj.(*langObjectList).elements())
+	case objectTypeArray:
+		return writeJSONArray(b, obj.(*langObjectArray).elements)
+	case objectTypeDict:
+		return writeJSONObject(b, obj.(*langObjectDict).keys, obj.(*langObjectDict).values)
+	case objectTypeRecord:
+		record := obj.(*langObjectRecord)
+		keys := make([]langObject, 0)
+
+		for _, field := range record.typ.fields {
+			keys = append(keys, &langObjectString{field,})
+		}
+
+		return writeJSONObject(b, keys, record.values)
+	default:
+		return fmt.Errorf("A %s cannot be written as JSON.", objectTypeName(obj.getType()))
+	}
+
+	return nil
+}
+
+func performJSONOperation(typ operationType, s *stack, v *variableScope, st *symbolTable) error {
+
+	switch typ {
+	case operationTypeJSONParse:
+		text, err1 := s.popString("a JSON string")
+
+		if err1 != nil {
+			return err1
+		}
+
+		/* Malformed JSON usually comes from outside the program, so it gives an error value */
+		value, err2 := parseJSON(text)
+
+		if err2 != nil {
+			err2 = errors.New("Invalid JSON: " + err2.Error())
+		}
+
+		s.pushResult(value, err2)
+	case operationTypeJSONStringify, operationTypeJSONPretty:
+		indent := ""
+
+		if typ == operationTypeJSONPretty {
+			/* The indentation is a number of spaces, or a string such as "\t" */
+			indentObj, err1 := s.pop()
+
+			if err1 != nil {
+				return err1
+			}
+
+			switch indentObj.getType() {
+			case objectTypeNumber:
+				spaces, spacesErr := wholeNumberIn(indentObj.(*langObjectNumber).val, 0, maxJSONIndent, "the number of spaces to indent with")
+
+				if spacesErr != nil {
+					return spacesErr
+				}
+
+				indent = strings.Repeat(" ", int(spaces))
+			case objectTypeString:
+				indent = indentObj.(*langObjectString).val
+			default:
+				return errors.New("Expected, but did not receive a number of spaces or a string to indent with.")
+			}
+		}
+
+		obj, err2 := s.pop()
+
+		if err2 != nil {
+			return err2
+		}
+
+		b := &strings.Builder{}
+
+		err3 := writeJSON(b, obj)
+
+		if err3 != nil {
+			return err3
+		}
+
+		if typ == operationTypeJSONPretty {
+			var pretty bytes.Buffer
+
+			err4 := json.Indent(&pretty, []byte(b.String()), "", indent)
+
+			if err4 != nil {
+				return err4
+			}
+
+			s.push(&langObjectString{pretty.String(),})
+		} else {
+			s.push(&langObjectString{b.String(),})
+		}
+	default:
+		return errors.New("Invalid JSON operation.")
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func stringifyJSON(t *testing.T, obj langObject) string {
+	b := &strings.Builder{}
+
+	err := writeJSON(b, obj)
+
+	if err != nil {
+		t.Fatalf("Unable to write %s as JSON: %s", obj.toString(), err)
+	}
+
+	return b.String()
+}
+
+/* Parsing JSON, writing it out and parsing it again should give the same value */
+func TestJSONRoundTrip(t *testing.T) {
+	tests := []string{
+		`[1, "a", true, false, null, [2.5, []]]`,
+		`{"name": "Ian", "age": 18, "tags": {"b": [1, 2], "a": null}}`,
+		`-325.5`,
+		`1e21`,
+		`"quote \" backslash \\ newline \n unicode é"`,
+		`true`,
+		`false`,
+		`null`,
+		`{}`,
+	}
+
+	for _, test := range tests {
+		first, err1 := parseJSON(test)
+
+		if err1 != nil {
+			t.Errorf("Unable to parse %s: %s", test, err1)
+			continue
+		}
+
+		text := stringifyJSON(t, first)
+
+		second, err2 := parseJSON(text)
+
+		if err2 != nil {
+			t.Errorf("Unable to parse %s, written from %s: %s", text, test, err2)
+			continue
+		}
+
+		equal, err3 := first.equals(second)
+
+		if err3 != nil || !equal {
+			t.Errorf("%s became %s after a round trip through %s", first.toString(), second.toString(), text)
+		}
+	}
+}
+
+/* Writing JSL values out and parsing them again should give them back */
+func TestJSONValues(t *testing.T) {
+	dict := newDict()
+	dict.put(&langObjectString{"list",}, newList([]langObject{&langObjectNumber{1,}, &langObjectString{"two",}, intern("None"),}))
+	dict.put(&langObjectString{"missing",}, intern("None"))
+	dict.put(&langObjectString{"yes",}, &langObjectBoolean{true,})
+
+	tests := []langObject{
+		newList([]langObject{}),
+		newList([]langObject{&langObjectNumber{1,}, &langObjectNumber{-0.5,}, newList([]langObject{&langObjectBoolean{false,},}),}),
+		dict,
+		&langObjectNumber{42,},
+		&langObjectString{"tab\tand \"quotes\"",},
+		&langObjectBoolean{true,},
+		intern("None"),
+	}
+
+	for _, test := range tests {
+		text := stringifyJSON(t, test)
+
+		parsed, err1 := parseJSON(text)
+
+		if err1 != nil {
+			t.Errorf("Unable to parse %s, written from %s: %s", text, test.toString(), err1)
+			continue
+		}
+
+		equal, err2 := test.equals(parsed)
+
+		if err2 != nil || !equal {
+			t.Errorf("%s became %s after a round trip through %s", test.toString(), parsed.toString(), text)
+		}
+	}
+}
+
+/* Object keys keep the order they were written in */
+func TestJSONKeyOrder(t *testing.T) {
+	text := `{"z":1,"a":2,"m":3}`
+
+	obj, err := parseJSON(text)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if written := stringifyJSON(t, obj); written != text {
+		t.Errorf("Expected %s, but wrote %s", text, written)
+	}
+}
+
+func TestJSONPrettyIndent(t *testing.T) {
+	tests := []struct {
+		indent langObject
+		expected string
+		fails bool
+	}{
+		{&langObjectNumber{2,}, "[\n  1\n]", false},
+		{&langObjectString{"\t",}, "[\n\t1\n]", false},
+		{&langObjectNumber{-1,}, "", true},
+		{&langObjectNumber{1.5,}, "", true},
+		{&langObjectNumber{17,}, "", true},
+		{&langObjectNumber{1e18,}, "", true},
+		{&langObjectNumber{1e300,}, "", true},
+		{&langObjectBoolean{true,}, "", true},
+	}
+
+	for _, test := range tests {
+		s, v, st, _ := newTestProgram()
+		s.push(newList([]langObject{&langObjectNumber{1,},}))
+		s.push(test.indent)
+
+		err := performJSONOperation(operationTypeJSONPretty, s, v, st)
+
+		if test.fails {
+			if err == nil {
+				t.Errorf("Expected indenting by %s to fail", test.indent.toString())
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Unable to indent by %s: %s", test.indent.toString(), err)
+			continue
+		}
+
+		if result, _ := s.pop(); result.toString() != test.expected {
+			t.Errorf("Expected %q, but received %q", test.expected, result.toString())
+		}
+	}
+}
+
+func TestJSONInvalid(t *testing.T) {
+	for _, test := range []string{`[1,`, `{"a" 1}`, `1 2`, ``, `nul`} {
+		if _, err := parseJSON(test); err == nil {
+			t.Errorf("Expected %q not to parse", test)
+		}
+	}
+}
@@ -34,11 +34,17 @@ const (
 	itemCondition
 	itemEmptyList
 	itemCons
+	itemOpenList
+	itemCloseList
+	itemOpenDict
+	itemStackEffect
+	itemParameters
 )
 
 type item struct {
 	typ itemType
 	val string
+	line int
 }
 
 func (item *item) print() {
@@ -79,6 +85,16 @@ func (item *item) print() {
 		itemTypeString = "itemEmptyList"
 	case itemCons:
 		itemTypeString = "itemCons"
+	case itemOpenList:
+		itemTypeString = "itemOpenList"
+	case itemCloseList:
+		itemTypeString = "itemCloseList"
+	case itemOpenDict:
+		itemTypeString = "itemOpenDict"
+	case itemStackEffect:
+		itemTypeString = "itemStackEffect"
+	case itemParameters:
+		itemTypeString = "itemParameters"
 	}
 
 	if printValue {
@@ -94,14 +110,15 @@ type lexer struct {
 	start int
 	pos int
 	width int
+	line int
 	items chan item
 }
 
 type stateFn func(*lexer) stateFn
 
 func (l *lexer) emit(t itemType) {
-	l.items <- item{t, l.input[l.start:l.pos]}
-	l.start = l.pos
+	l.items <- item{t, l.input[l.start:l.pos], l.line}
+	l.ignore()
 }
 
 func (l *lexer) next() (rune) {
@@ -119,7 +136,9 @@ func (l *lexer) next() (rune) {
 	return r
 }
 
+/* Skips over the input read since the last item, keeping count of the lines */
 func (l *lexer) ignore() {
+	l.line += strings.Count(l.input[l.start:l.pos], "\n")
 	l.start = l.pos
 }
 
@@ -147,10 +166,57 @@ func (l *lexer) acceptRun(valid string) {
 	l.backup()
 }
 
+func (l *lexer) acceptJoiner() bool {
+	pos := l.pos
+
+	switch {
+	case l.accept("-"):
+		l.accept(">")
+	case l.accept(":"):
+		/* A namespaced name such as dict:insert */
+	default:
+		return false
+	}
+
+	/* A digit after a hyphen starts a number, so that x-1 is still x followed by -1 */
+	if n := l.peek(); strings.IndexRune(identifierRunes, n) >= 0 && !unicode.IsDigit(n) {
+		return true
+	}
+
+	/* Not part of the identifier, so leave the '-', '->' or ':' for lexCode */
+	l.pos = pos
+	return false
+}
+
+/*	Stack words that begin with a character that would otherwise start a subtraction or a number.
+	Elsewhere, -x is still - followed by x, and 2x is 2 followed by x. */
+var signedWords = []string{"-rot", "2dup", "2drop"}
+
+/* Accepts one of the signed words, if the input since the start of the item begins with it */
+func (l *lexer) acceptWord() bool {
+	rest := l.input[l.start:]
+
+	for _, word := range signedWords {
+		if !strings.HasPrefix(rest, word) {
+			continue
+		}
+
+		if len(rest) > len(word) && strings.IndexRune(identifierRunes, rune(rest[len(word)])) >= 0 {
+			continue
+		}
+
+		l.pos = l.start + len(word)
+		return true
+	}
+
+	return false
+}
+
 func (l *lexer) errorf(format string, args ...interface{}) stateFn {
 	l.items <- item {
 		itemError,
 		fmt.Sprintf(format, args...),
+		l.line,
 	}
 
 	return nil
@@ -191,6 +257,11 @@ func lexIdentifier(l *lexer) stateFn {
 	if l.start == l.pos {
 		return l.errorf("Empty identifier at position %d", l.start)
 	}
+
+	/* Words such as read-line, list->stack and dict:insert are joined by hyphens, arrows and colons */
+	for l.acceptJoiner() {
+		l.acceptRun(identifierRunes)
+	}
 	
 	l.emit(identifierType)
 	return lexCode
@@ -256,6 +327,7 @@ func lexComment(l *lexer) stateFn {
 			case eof:
 				return l.errorf("Unexpected end of file.")
 			case ')':
+				l.ignore()
 				return lexCode
 			default:
 				// Continue in the comment
@@ -267,6 +339,42 @@ func lexComment(l *lexer) stateFn {
 
 }
 
+func lexStackEffect(l *lexer) stateFn {
+
+	for c := l.next(); c != ')'; c = l.next() {
+
+		if c == eof {
+			return l.errorf("Unexpected end of file.")
+		}
+	}
+
+	l.backup()
+	l.emit(itemStackEffect)
+
+	l.next()
+	l.ignore() // We don't want the closing parenthesis
+
+	return lexCode
+}
+
+func lexParameters(l *lexer) stateFn {
+
+	for c := l.next(); c != '|'; c = l.next() {
+
+		if c == eof {
+			return l.errorf("Unexpected end of file.")
+		}
+	}
+
+	l.backup()
+	l.emit(itemParameters)
+
+	l.next()
+	l.ignore() // We don't want the closing bar
+
+	return lexCode
+}
+
 func lexCode(l *lexer) stateFn {
 
 	switch r := l.next(); {
@@ -285,6 +393,9 @@ func lexCode(l *lexer) stateFn {
 	case r == '/':
 		l.emit(itemDividedBy)
 		return lexCode
+	case r == '-' && l.acceptWord():
+		l.emit(itemIdentifier)
+		return lexCode
 	case r == '-':
 		if n := l.peek(); '0' <= n && n <= '9' {
 			l.backup()
@@ -299,6 +410,12 @@ func lexCode(l *lexer) stateFn {
 	case r == '}':
 		l.emit(itemEndBlock)
 		return lexCode
+	case r == '[':
+		l.emit(itemOpenList)
+		return lexCode
+	case r == ']':
+		l.emit(itemCloseList)
+		return lexCode
 	case r == '@':
 		l.emit(itemAt)
 		return lexCode
@@ -338,6 +455,9 @@ func lexCode(l *lexer) stateFn {
 	case r == '"':
 		l.ignore() // We don't want the beginning quote
 		return lexQuotedString
+	case '0' <= r && r <= '9' && l.acceptWord():
+		l.emit(itemIdentifier)
+		return lexCode
 	case '0' <= r && r <= '9':
 		l.backup()
 		return lexNumber
@@ -358,11 +478,23 @@ func lexCode(l *lexer) stateFn {
 
 		l.emit(itemExecute)
 		return lexCode
+	case r == '#':
+		if l.next() != '{' {
+			return l.errorf("Unexpected '#' at position %d", l.start)
+		}
+
+		l.emit(itemOpenDict)
+		return lexCode
+	case r == '|':
+		l.ignore() // We don't want the opening bar
+		return lexParameters
 	case r == '(':
-		if l.next() != '*' {
-			return l.errorf("Unexpected '(' at position %d", l.start)
+		if l.peek() != '*' {
+			l.ignore() // We don't want the opening parenthesis
+			return lexStackEffect
 		}
 
+		l.next()
 		return lexComment
 	default:
 		return l.errorf("Unexpected %q at position %d", r, l.start)
@@ -373,6 +505,7 @@ func lex(name, input string) (*lexer, chan item) {
 	l := &lexer {
 		name: name,
 		input: input,
+		line: 1,
 		items: make(chan item),
 	}
 
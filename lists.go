@@ -0,0 +1,418 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+/* Pushes each argument, calls the code block, and pops the single result it leaves behind */
+func callForResult(block *langObjectCodeBlock, s *stack, st *symbolTable, in *interpreter, args ...langObject) (langObject, error) {
+
+	for _, arg := range args {
+		s.push(arg)
+	}
+
+	err1 := block.call(s, st, in)
+
+	if err1 != nil {
+		return nil, err1
+	}
+
+	return s.pop()
+}
+
+func callForBoolean(block *langObjectCodeBlock, s *stack, st *symbolTable, in *interpreter, args ...langObject) (bool, error) {
+
+	result, err1 := callForResult(block, s, st, in, args...)
+
+	if err1 != nil {
+		return false, err1
+	}
+
+	if result.getType() != objectTypeBoolean {
+		return false, errors.New("Expected the code block to leave a boolean on the stack.")
+	}
+
+	return result.(*langObjectBoolean).val, nil
+}
+
+/* The default sort order: values of the same type by value, values of different types by type */
+func sortLess(l langObject, o langObject) (bool, error) {
+	if l.getType() != o.getType() {
+		return typeRank(l.getType()) < typeRank(o.getType()), nil
+	}
+
+	if l.getType() != objectTypeList {
+		return l.lessThan(o)
+	}
+
+	elements, otherElements := l.(*langObjectList).elements(), o.(*langObjectList).elements()
+
+	for i := 0; i < len(elements) && i < len(otherElements); i++ {
+		equal, eqErr := elements[i].equals(otherElements[i])
+
+		if eqErr != nil {
+			return false, eqErr
+		}
+
+		if !equal {
+			return sortLess(elements[i], otherElements[i])
+		}
+	}
+
+	return len(elements) < len(otherElements), nil
+}
+
+func performListOperation(typ operationType, s *stack, v *variableScope, st *symbolTable, in *interpreter) error {
+
+	switch typ {
+	case operationTypeListLength:
+		obj, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		switch obj.getType() {
+		case objectTypeList:
+			s.push(&langObjectNumber{float64(len(obj.(*langObjectList).elements())),})
+		case objectTypeArray:
+			s.push(&langObjectNumber{float64(len(obj.(*langObjectArray).elements)),})
+		default:
+			return errors.New("Expected, but did not get a list or an array.")
+		}
+	case operationTypeListReverse:
+		list, err1 := s.popList()
+
+		if err1 != nil {
+			return err1
+		}
+
+		reversed := &langObjectList{true, nil, nil,}
+
+		for _, obj := range list.elements() {
+			reversed = &langObjectList{false, obj, reversed,}
+		}
+
+		s.push(reversed)
+	case operationTypeListMap:
+		block, err1 := s.popCodeBlock()
+
+		if err1 != nil {
+			return err1
+		}
+
+		list, err2 := s.popList()
+
+		if err2 != nil {
+			return err2
+		}
+
+		results := make([]langObject, 0)
+
+		for _, obj := range list.elements() {
+			result, err3 := callForResult(block, s, st, in, obj)
+
+			if err3 != nil {
+				return err3
+			}
+
+			results = append(results, result)
+		}
+
+		s.push(newList(results))
+	case operationTypeListFilter:
+		block, err1 := s.popCodeBlock()
+
+		if err1 != nil {
+			return err1
+		}
+
+		list, err2 := s.popList()
+
+		if err2 != nil {
+			return err2
+		}
+
+		results := make([]langObject, 0)
+
+		for _, obj := range list.elements() {
+			keep, err3 := callForBoolean(block, s, st, in, obj)
+
+			if err3 != nil {
+				return err3
+			}
+
+			if keep {
+				results = append(results, obj)
+			}
+		}
+
+		s.push(newList(results))
+	case operationTypeListFold, operationTypeListReduce:
+		block, err1 := s.popCodeBlock()
+
+		if err1 != nil {
+			return err1
+		}
+
+		var acc langObject
+
+		if typ == operationTypeListFold {
+			initial, err2 := s.pop()
+
+			if err2 != nil {
+				return err2
+			}
+
+			acc = initial
+		}
+
+		list, err3 := s.popList()
+
+		if err3 != nil {
+			return err3
+		}
+
+		elements := list.elements()
+
+		if typ == operationTypeListReduce {
+			if len(elements) == 0 {
+				return errors.New("Unable to reduce an empty list.")
+			}
+
+			acc, elements = elements[0], elements[1:]
+		}
+
+		for _, obj := range elements {
+			result, err4 := callForResult(block, s, st, in, acc, obj)
+
+			if err4 != nil {
+				return err4
+			}
+
+			acc = result
+		}
+
+		s.push(acc)
+	case operationTypeListNth:
+		n, err1 := s.popNumber()
+
+		if err1 != nil {
+			return err1
+		}
+
+		list, err2 := s.popList()
+
+		if err2 != nil {
+			return err2
+		}
+
+		elements := list.elements()
+
+		if n != math.Trunc(n) || n < 0 || int(n) >= len(elements) {
+			return fmt.Errorf("Index %v is out of range for a list of length %d.", n, len(elements))
+		}
+
+		s.push(elements[int(n)])
+	case operationTypeListAppend:
+		value, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		list, err2 := s.popList()
+
+		if err2 != nil {
+			return err2
+		}
+
+		s.push(newList(append(list.elements(), value)))
+	case operationTypeListConcat:
+		second, err1 := s.popList()
+
+		if err1 != nil {
+			return err1
+		}
+
+		first, err2 := s.popList()
+
+		if err2 != nil {
+			return err2
+		}
+
+		s.push(newList(append(first.elements(), second.elements()...)))
+	case operationTypeListRange:
+		end, err1 := s.popNumber()
+
+		if err1 != nil {
+			return err1
+		}
+
+		start, err2 := s.popNumber()
+
+		if err2 != nil {
+			return err2
+		}
+
+		/* The end is exclusive, and the range counts down if it is below the start */
+		step := 1.0
+
+		if end < start {
+			step = -1.0
+		}
+
+		results := make([]langObject, 0)
+
+		for i := start; (step > 0 && i < end) || (step < 0 && i > end); i += step {
+			results = append(results, &langObjectNumber{i,})
+		}
+
+		s.push(newList(results))
+	case operationTypeListZip:
+		second, err1 := s.popList()
+
+		if err1 != nil {
+			return err1
+		}
+
+		first, err2 := s.popList()
+
+		if err2 != nil {
+			return err2
+		}
+
+		firstElements, secondElements := first.elements(), second.elements()
+
+		results := make([]langObject, 0)
+
+		for i := 0; i < len(firstElements) && i < len(secondElements); i++ {
+			results = append(results, newList([]langObject{firstElements[i], secondElements[i]}))
+		}
+
+		s.push(newList(results))
+	case operationTypeListSort:
+		/* The comparison block is optional. It is given two elements and leaves true if the
+		   first belongs before the second. */
+		var compare *langObjectCodeBlock
+
+		top, err1 := s.peek()
+
+		if err1 != nil {
+			return err1
+		}
+
+		if top.getType() == objectTypeCodeBlock {
+			s.pop()
+			compare = top.(*langObjectCodeBlock)
+		}
+
+		list, err2 := s.popList()
+
+		if err2 != nil {
+			return err2
+		}
+
+		elements := list.elements()
+
+		var sortErr error
+
+		sort.SliceStable(elements, func(i, j int) bool {
+			if sortErr != nil {
+				return false
+			}
+
+			var less bool
+
+			if compare != nil {
+				less, sortErr = callForBoolean(compare, s, st, in, elements[i], elements[j])
+			} else {
+				less, sortErr = sortLess(elements[i], elements[j])
+			}
+
+			return less
+		})
+
+		if sortErr != nil {
+			return sortErr
+		}
+
+		s.push(newList(elements))
+	case operationTypeListAny, operationTypeListAll:
+		block, err1 := s.popCodeBlock()
+
+		if err1 != nil {
+			return err1
+		}
+
+		list, err2 := s.popList()
+
+		if err2 != nil {
+			return err2
+		}
+
+		/* any? stops at the first true, all? at the first false */
+		result := typ == operationTypeListAll
+
+		for _, obj := range list.elements() {
+			test, err3 := callForBoolean(block, s, st, in, obj)
+
+			if err3 != nil {
+				return err3
+			}
+
+			if test != result {
+				result = test
+				break
+			}
+		}
+
+		s.push(&langObjectBoolean{result,})
+	case operationTypeListToStack:
+		list, err1 := s.popList()
+
+		if err1 != nil {
+			return err1
+		}
+
+		for _, obj := range list.elements() {
+			s.push(obj)
+		}
+	case operationTypeStackToList:
+		n, err1 := s.popNumber()
+
+		if err1 != nil {
+			return err1
+		}
+
+		if n != math.Trunc(n) || n < 0 {
+			return fmt.Errorf("Cannot collect %v items into a list.", n)
+		}
+
+		/* Checked before anything is allocated, so that a huge count fails cleanly */
+		if n > float64(len(s.contents)) {
+			return fmt.Errorf("Cannot collect %v items into a list, as there are only %d items on the stack.", n, len(s.contents))
+		}
+
+		results := make([]langObject, int(n))
+
+		for i := len(results) - 1; i >= 0; i-- {
+			obj, err2 := s.pop()
+
+			if err2 != nil {
+				return err2
+			}
+
+			results[i] = obj
+		}
+
+		s.push(newList(results))
+	default:
+		return errors.New("Invalid list operation.")
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+/* A fresh stack, scope, symbol table and interpreter, with no input and discarded output */
+func newTestProgram() (*stack, *variableScope, *symbolTable, *interpreter) {
+	s := &stack{make([]langObject, 0),nil,}
+	v := &variableScope{make(map[string]*langVariable),nil,}
+	st := &symbolTable{make(map[uint64]*symbolTableEntry),nil,}
+
+	return s, v, st, newInterpreterWithIO(strings.NewReader(""), io.Discard)
+}
+
+/* The recursive rev and list_map procedures from the README, built on :: and split */
+const readmeListProcedures = `
+	{
+		{
+			'acc asn
+			'list asn
+
+			{ acc } list empty? if
+			{
+				list split
+				'head asn
+				'tail asn
+
+				tail acc head :: rev_tr!
+			} list empty? ~ if
+		} 'rev_tr asn
+
+		<> rev_tr!
+	} 'rev asn
+
+	{
+		{
+			'acc asn
+			'list asn
+			'func asn
+
+			{ acc rev! } list empty? if
+			{
+				list split
+				'head asn
+				'tail asn
+
+				func tail acc head func! :: map_tr!
+			} list empty? ~ if
+		} 'map_tr asn
+
+		<> map_tr!
+	} 'list_map asn
+`
+
+/*	Runs code repeatedly against a list of each size, bound to xs, with the README's list procedures
+	defined. Lists share their structure, so :: should take the same time at every size, and rev and
+	list_map time in proportion to it. */
+func benchmarkListSizes(b *testing.B, code string) {
+	for _, size := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
+			s, v, st, in := newTestProgram()
+
+			err1 := evalString(fmt.Sprintf("%s 0 %d range 'xs asn", readmeListProcedures, size), "", s, v, st, in, false)
+
+
+			if err1 != nil {
+				b.Fatal(err1)
+			}
+
+			main, err2 := compile(code, "")
+
+			if err2 != nil {
+				b.Fatal(err2)
+			}
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				err3 := main.exec(s, v, st, in, false)
+
+				if err3 != nil {
+					b.Fatal(err3)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCons(b *testing.B) {
+	benchmarkListSizes(b, "xs 0 :: pop")
+}
+
+func BenchmarkRev(b *testing.B) {
+	benchmarkListSizes(b, "xs rev! pop")
+}
+
+func BenchmarkMap(b *testing.B) {
+	benchmarkListSizes(b, "{ 1 + } xs list_map! pop")
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/* Modules */
+
+/*	A module is a file that has been imported. It is evaluated once, in a scope of its own, and
+	only the names it exports are bound in the importing scope, under a namespace such as dict:. */
+type module struct {
+	path string
+	scope *variableScope
+	exports []string
+}
+
+/*	Finds the file for a module name such as "dict" or "lib/dict.jsl". Names are looked up in the
+	directory of the file doing the importing, then in each directory of JSL_PATH. Code typed at the
+	prompt or given to -n has no file, so its imports start from the working directory instead. */
+func (in *interpreter) resolveModule(name string) (string, error) {
+
+	if filepath.Ext(name) == "" {
+		name += ".jsl"
+	}
+
+	dirs := make([]string, 0)
+
+	if filepath.IsAbs(name) {
+		dirs = append(dirs, "")
+	} else {
+		if current := in.currentFile(); current != "" {
+			dirs = append(dirs, filepath.Dir(current))
+		} else {
+			dirs = append(dirs, ".")
+		}
+
+		dirs = append(dirs, in.searchPath...)
+	}
+
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name)
+
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return filepath.Abs(path)
+		}
+	}
+
+	return "", fmt.Errorf("Unable to find module '%s' in '%s'.", name, strings.Join(dirs, "', '"))
+}
+
+/* Loads a module, or returns it from the cache if it has already been imported */
+func (in *interpreter) importModule(name string, st *symbolTable) (*module, error) {
+
+	if !in.allows(capabilityFiles) {
+		return nil, errors.New("File system access is not allowed.")
+	}
+
+	path, err1 := in.resolveModule(name)
+
+	if err1 != nil {
+		return nil, err1
+	}
+
+	if mod, ok := in.modules[path]; ok {
+		return mod, nil
+	}
+
+	for i, loading := range in.loading {
+		if loading.path == path {
+			cycle := make([]string, 0)
+
+			for _, mod := range in.loading[i:] {
+				cycle = append(cycle, filepath.Base(mod.path))
+			}
+
+			return nil, fmt.Errorf("Import cycle: %s -> %s.", strings.Join(cycle, " -> "), filepath.Base(path))
+		}
+	}
+
+	mod := &module{path, &variableScope{make(map[string]*langVariable),nil,}, make([]string, 0),}
+
+	in.loading = append(in.loading, mod)
+
+	/* Anything the module leaves on its stack is discarded */
+	err2 := in.evalFile(path, &stack{make([]langObject, 0),nil,}, mod.scope, st)
+
+	in.loading = in.loading[:len(in.loading) - 1]
+
+	if err2 != nil {
+		return nil, err2
+	}
+
+	for _, name := range mod.exports {
+		if _, ok := mod.scope.get(name); ok == false {
+			return nil, fmt.Errorf("Module %s exports '%s', but does not define it.", filepath.Base(path), name)
+		}
+	}
+
+	in.modules[path] = mod
+
+	return mod, nil
+}
+
+/* The namespace a module is imported under if none is given: its file name, without the extension */
+func moduleNamespace(name string) string {
+	base := filepath.Base(name)
+
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+func performModuleOperation(typ operationType, s *stack, v *variableScope, st *symbolTable, in *interpreter) error {
+
+	switch typ {
+	case operationTypeImport:
+		top, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		/* The namespace may be given as a symbol above the module name */
+		namespace, hasNamespace := symbolName(top)
+
+		if hasNamespace {
+			top, err1 = s.pop()
+
+			if err1 != nil {
+				return err1
+			}
+		}
+
+		if top.getType() != objectTypeString {
+			return errors.New("Expected, but did not receive a module name.")
+		}
+
+		name := top.(*langObjectString).val
+
+		if !hasNamespace {
+			namespace = moduleNamespace(name)
+		}
+
+		mod, err2 := in.importModule(name, st)
+
+		if err2 != nil {
+			return err2
+		}
+
+		for _, export := range mod.exports {
+			key, _ := mod.scope.get(export)
+			value, _ := st.retrieve(key)
+
+			err3 := retain(value, st)
+
+			if err3 != nil {
+				return err3
+			}
+
+			err4 := assignVariable(operationTypeAssign, namespace + ":" + export, value, v, st)
+
+			if err4 != nil {
+				return err4
+			}
+		}
+	case operationTypeExport:
+		obj, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		names := []langObject{obj}
+
+		if obj.getType() == objectTypeList {
+			names = obj.(*langObjectList).elements()
+		}
+
+		mod := in.currentModule()
+
+		for _, nameObj := range names {
+			name, ok := symbolName(nameObj)
+
+			if ok == false {
+				return errors.New("Expected, but did not receive a symbol or a list of symbols to export.")
+			}
+
+			/* A file that is run or included rather than imported has nothing to export to */
+			if mod == nil {
+				continue
+			}
+
+			exported := false
+
+			for _, export := range mod.exports {
+				exported = exported || export == name
+			}
+
+			if !exported {
+				mod.exports = append(mod.exports, name)
+			}
+		}
+	default:
+		return errors.New("Invalid module operation.")
+	}
+
+	return nil
+}
@@ -3,8 +3,9 @@ package main
 import (
 	"errors"
 	"fmt"
-	"os"
-	"bufio"
+	"path/filepath"
+	"strconv"
+	"strings"
 )
 
 func evaluateCondition(typ operationType, s *stack, v *variableScope, st *symbolTable) error {
@@ -97,6 +98,39 @@ func evaluateCondition(typ operationType, s *stack, v *variableScope, st *symbol
 	return nil
 }
 
+/* Binds a value to a variable name in the given scope, as asn and lasn do */
+func assignVariable(typ operationType, name string, val langObject, v *variableScope, st *symbolTable) error {
+
+	/* See if the name refers to an already defined variable */
+
+	stKey, ok := v.get(name)
+
+	if ok == true {
+		/* Garbage collection */
+		err1 := st.decReference(stKey)
+
+		if err1 != nil {
+			return err1
+		}
+	}
+
+	/* Insert the new variable value */
+	valKey, err2 := st.insert(val)
+
+	if err2 != nil {
+		return err2
+	}
+
+	switch typ {
+	case operationTypeAssign:
+		v.set(name, valKey)
+	case operationTypeLocalAssign:
+		v.setLocal(name, valKey)
+	}
+
+	return nil
+}
+
 func performAssign(typ operationType, s *stack, v *variableScope, st *symbolTable) error {
 
 	reference, err1 := s.pop()
@@ -118,34 +152,7 @@ func performAssign(typ operationType, s *stack, v *variableScope, st *symbolTabl
 			return errors.New("Expected, but did not receive an identifier reference.")
 		}
 
-		/* See if the reference points to an already defined variable */
-
-		stKey, ok := v.get(ident.name)
-
-		if ok == true {
-			/* Garbage collection */
-			err4 := st.decReference(stKey)
-
-			if err4 != nil {
-				return err4
-			}
-		}
-
-		/* Insert the new variable value */
-		valKey, err5 := st.insert(val)
-
-		if err5 != nil {
-			return err5
-		}
-
-		switch typ {
-		case operationTypeAssign:
-			v.set(ident.name, valKey)
-		case operationTypeLocalAssign:
-			v.setLocal(ident.name, valKey)
-		}
-
-		
+		return assignVariable(typ, ident.name, val, v, st)
 	} else if reference.getType() == objectTypeReference {
 
 		ref := reference.(*langObjectReference)
@@ -171,7 +178,22 @@ func performAssign(typ operationType, s *stack, v *variableScope, st *symbolTabl
 	return nil
 }
 
-func performOperation(typ operationType, s *stack, v *variableScope, st *symbolTable) error {
+/* The type each predicate such as number? tests for */
+var typePredicates = map[operationType]langObjectType{
+	operationTypeIsNumber: objectTypeNumber,
+	operationTypeIsString: objectTypeString,
+	operationTypeIsBoolean: objectTypeBoolean,
+	operationTypeIsBlock: objectTypeCodeBlock,
+	operationTypeIsList: objectTypeList,
+	operationTypeIsReference: objectTypeReference,
+	operationTypeIsError: objectTypeError,
+	operationTypeIsSymbol: objectTypeSymbol,
+	operationTypeIsDict: objectTypeDict,
+	operationTypeIsArray: objectTypeArray,
+	operationTypeIsRecord: objectTypeRecord,
+}
+
+func performOperation(typ operationType, s *stack, v *variableScope, st *symbolTable, in *interpreter) error {
 
 	/*fmt.Println("---")
 	s.print()
@@ -307,8 +329,7 @@ func performOperation(typ operationType, s *stack, v *variableScope, st *symbolT
 			return errors.New("Expected, but did not receive a code block.")
 		}
 
-		newScope := &variableScope{make(map[string]*langVariable),block.(*langObjectCodeBlock).parentScope,}
-		err2 := block.(*langObjectCodeBlock).exec(s, newScope, st, true)
+		err2 := block.(*langObjectCodeBlock).call(s, st, in)
 
 		if err2 != nil {
 			return err2
@@ -435,8 +456,7 @@ func performOperation(typ operationType, s *stack, v *variableScope, st *symbolT
 
 		if boolObj.getValue().(bool) {
 
-			newScope := &variableScope{make(map[string]*langVariable),codeBlockObj.(*langObjectCodeBlock).parentScope,}
-			err3 := codeBlockObj.(*langObjectCodeBlock).exec(s, newScope, st, true)
+			err3 := codeBlockObj.(*langObjectCodeBlock).call(s, st, in)
 
 			if err3 != nil {
 				return err3
@@ -481,7 +501,7 @@ func performOperation(typ operationType, s *stack, v *variableScope, st *symbolT
 
 		conditionScope := &variableScope{make(map[string]*langVariable), bodyCodeBlock.(*langObjectCodeBlock).parentScope,}
 
-		initErr := initialCodeBlock.(*langObjectCodeBlock).exec(s, conditionScope, st, false)
+		initErr := initialCodeBlock.(*langObjectCodeBlock).exec(s, conditionScope, st, in, false)
 
 		if initErr != nil {
 			return initErr
@@ -489,7 +509,7 @@ func performOperation(typ operationType, s *stack, v *variableScope, st *symbolT
 
 		for true {
 			
-			condErr := conditionCodeBlock.(*langObjectCodeBlock).exec(s, conditionScope, st, false)
+			condErr := conditionCodeBlock.(*langObjectCodeBlock).exec(s, conditionScope, st, in, false)
 
 			if condErr != nil {
 				return condErr
@@ -511,13 +531,13 @@ func performOperation(typ operationType, s *stack, v *variableScope, st *symbolT
 
 			bodyScope := &variableScope{make(map[string]*langVariable), conditionScope,}
 
-			bodyErr := bodyCodeBlock.(*langObjectCodeBlock).exec(s, bodyScope, st, true)
+			bodyErr := bodyCodeBlock.(*langObjectCodeBlock).exec(s, bodyScope, st, in, true)
 
 			if bodyErr != nil {
 				return bodyErr
 			}
 
-			afterErr := afterCodeBlock.(*langObjectCodeBlock).exec(s, conditionScope, st, false)
+			afterErr := afterCodeBlock.(*langObjectCodeBlock).exec(s, conditionScope, st, in, false)
 
 			if afterErr != nil {
 				return afterErr
@@ -569,7 +589,7 @@ func performOperation(typ operationType, s *stack, v *variableScope, st *symbolT
 		s.push(&langObjectList{
 			false,
 			value,
-			list.copy().(*langObjectList),
+			list.(*langObjectList),
 		})
 	case operationTypeListSplit:
 
@@ -587,16 +607,22 @@ func performOperation(typ operationType, s *stack, v *variableScope, st *symbolT
 			return errors.New("Unable to split an empty list.")
 		}
 
-		s.push(list.(*langObjectList).tail.copy())
+		s.push(list.(*langObjectList).tail)
 		s.push(list.(*langObjectList).head)
 	case operationTypePop:
 
-		_, err1 := s.pop()
+		obj, err1 := s.pop()
 
 		if err1 != nil {
 			return err1
 		}
-	case operationTypeInclude:
+
+		err2 := release(obj, st)
+
+		if err2 != nil {
+			return err2
+		}
+	case operationTypeInclude, operationTypeIncludeOnce:
 
 		filePath, err1 := s.pop()
 
@@ -608,27 +634,133 @@ func performOperation(typ operationType, s *stack, v *variableScope, st *symbolT
 			return errors.New("Expected, but did not get a string.")
 		}
 
-		includeFile, err2 := os.Open(filePath.(*langObjectString).val)
+		if !in.allows(capabilityFiles) {
+			return errors.New("File system access is not allowed.")
+		}
+
+		path := in.resolvePath(filePath.(*langObjectString).val)
+
+		absPath, err2 := filepath.Abs(path)
 
 		if err2 != nil {
 			return err2
 		}
 
-		fileScanner := bufio.NewScanner(includeFile)
+		for i, file := range in.files {
+			if including, _ := filepath.Abs(file); including == absPath {
+				return fmt.Errorf("Include cycle: %s -> %s.", strings.Join(in.files[i:], " -> "), path)
+			}
+		}
+
+		/* include-once does nothing if the file has already been included, from anywhere */
+		if typ == operationTypeIncludeOnce && in.included[absPath] {
+			return nil
+		}
+
+		in.included[absPath] = true
 
-		includeFileContents := ""
+		return in.evalFile(path, s, v, st)
+	case operationTypeFormat:
+		return performFormat(s, v, st)
+	case operationTypeStringToNumber:
+		str, err1 := s.popString("a string")
 
-		for fileScanner.Scan() {
-			includeFileContents += fileScanner.Text() + " "
+		if err1 != nil {
+			return err1
 		}
 
-		err3 := evalString(includeFileContents, s, v, st, false)
+		/* Text that is not a number gives an error value, since it usually comes from input */
+		number, err2 := strconv.ParseFloat(strings.TrimSpace(str), 64)
 
-		if err3 != nil {
-			return err3
+		if err2 != nil {
+			s.push(&langObjectError{fmt.Sprintf("'%s' is not a number.", str),})
+		} else {
+			s.push(&langObjectNumber{number,})
+		}
+	case operationTypeListLength, operationTypeListReverse, operationTypeListMap, operationTypeListFilter,
+		operationTypeListFold, operationTypeListReduce, operationTypeListNth, operationTypeListAppend,
+		operationTypeListConcat, operationTypeListRange, operationTypeListZip, operationTypeListSort,
+		operationTypeListAny, operationTypeListAll, operationTypeListToStack, operationTypeStackToList:
+		return performListOperation(typ, s, v, st, in)
+	case operationTypeListBegin, operationTypeDictBegin:
+		s.mark()
+	case operationTypeListEnd:
+		elements, err1 := s.collect()
+
+		if err1 != nil {
+			return err1
+		}
+
+		s.push(newList(elements))
+	case operationTypeGet:
+		/* get works on dictionaries, arrays and records, so it goes to the operations of the container beneath the key */
+		err1 := s.require(2)
+
+		if err1 != nil {
+			return err1
+		}
+
+		switch s.contents[len(s.contents) - 2].getType() {
+		case objectTypeArray:
+			return performArrayOperation(typ, s, v, st)
+		case objectTypeRecord:
+			return performRecordOperation(typ, s, v, st)
+		}
+
+		return performDictOperation(typ, s, v, st, in)
+	case operationTypeDictEnd, operationTypeDictPut, operationTypeDictHas, operationTypeDictDelete,
+		operationTypeDictKeys, operationTypeDictValues, operationTypeDictSize, operationTypeDictEach:
+		return performDictOperation(typ, s, v, st, in)
+	case operationTypeArrayNew, operationTypeArraySet, operationTypeArrayPush, operationTypeArrayPop, operationTypeArraySlice,
+		operationTypeListToArray, operationTypeArrayToList, operationTypeClone:
+		return performArrayOperation(typ, s, v, st)
+	case operationTypeDefineRecord, operationTypeNewRecord, operationTypeRecordWith, operationTypeRecordIs:
+		return performRecordOperation(typ, s, v, st)
+	case operationTypeSwap, operationTypeOver, operationTypeRot, operationTypeReverseRot, operationTypeNip, operationTypeTuck,
+		operationTypePick, operationTypeRoll, operationTypeTwoDup, operationTypeTwoDrop, operationTypeDepth,
+		operationTypeDip, operationTypeKeep, operationTypeBi:
+		return performStackOperation(typ, s, v, st, in)
+	case operationTypeImport, operationTypeExport:
+		return performModuleOperation(typ, s, v, st, in)
+	case operationTypePrint, operationTypePrintLine, operationTypeEmit, operationTypeReadLine, operationTypeReadAll:
+		return performIOOperation(typ, s, v, st, in)
+	case operationTypeReadFile, operationTypeWriteFile, operationTypeAppendFile, operationTypeListDir, operationTypeExists,
+		operationTypeStat, operationTypeMakeDir, operationTypeRemove, operationTypeGlob:
+		return performFileOperation(typ, s, v, st, in)
+	case operationTypeJSONParse, operationTypeJSONStringify, operationTypeJSONPretty:
+		return performJSONOperation(typ, s, v, st)
+	case operationTypeCSVRead, operationTypeCSVReadFile, operationTypeCSVWrite:
+		return performCSVOperation(typ, s, v, st, in)
+	case operationTypeRegexMatch, operationTypeRegexFind, operationTypeRegexFindAll, operationTypeRegexReplace, operationTypeRegexSplit:
+		return performRegexOperation(typ, s, v, st, in)
+	case operationTypeGetenv, operationTypeSetenv, operationTypeNow, operationTypeTimeFormat, operationTypeTimeParse, operationTypeSleep, operationTypeExit, operationTypeArgs:
+		return performHostOperation(typ, s, v, st, in)
+	case operationTypeExec, operationTypeExecLines:
+		return performProcessOperation(typ, s, v, st, in)
+	case operationTypeRand, operationTypeRandInt, operationTypeShuffle, operationTypeChoice, operationTypeSeed:
+		return performRandomOperation(typ, s, v, st, in)
+	case operationTypeTypeOf, operationTypeIsNumber, operationTypeIsString, operationTypeIsBoolean, operationTypeIsBlock, operationTypeIsList,
+		operationTypeIsReference, operationTypeIsError, operationTypeIsSymbol, operationTypeIsDict, operationTypeIsArray, operationTypeIsRecord:
+		obj, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
 		}
 
-		includeFile.Close()
+		/* Garbage collection */
+		if obj.getType() == objectTypeReference {
+			err2 := st.decReference(obj.(*langObjectReference).key)
+
+			if err2 != nil {
+				return err2
+			}
+		}
+
+		if typ == operationTypeTypeOf {
+			s.push(intern(objectTypeName(obj.getType())))
+		} else {
+			s.push(&langObjectBoolean{obj.getType() == typePredicates[typ],})
+		}
 	}
 
 	
@@ -3,12 +3,14 @@ package main
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"regexp"
 )
 
 type parser struct {
 	lexerItems chan item
-	nestedLevel int
+	file string
+	line int
 }
 
 func parseString(str string) string {
@@ -27,7 +29,11 @@ func parseString(str string) string {
 
 func parseIdentifier(i item) langObject {
 	
-	if i.typ == itemIdentifierReference || i.typ == itemIdentifierReferenceAt || i.typ == itemIdentifierCall  || i.typ == itemIdentifierName {
+	if i.typ == itemIdentifierName {
+		return intern(i.val)
+	}
+
+	if i.typ == itemIdentifierReference || i.typ == itemIdentifierReferenceAt || i.typ == itemIdentifierCall {
 		identifierTyp := identifierReference
 
 		if i.typ == itemIdentifierReferenceAt {
@@ -38,10 +44,6 @@ func parseIdentifier(i item) langObject {
 			identifierTyp = identifierCall
 		}
 
-		if i.typ == itemIdentifierName {
-			identifierTyp = identifierName
-		}
-
 		return &langObjectIdentifier{identifierTyp, i.val,}
 	} else {
 		switch {
@@ -67,8 +69,222 @@ func parseIdentifier(i item) langObject {
 			return &langObjectOperation{operationTypeListSplit,}
 		case i.val == "pop":
 			return &langObjectOperation{operationTypePop,}
+		case i.val == "swap":
+			return &langObjectOperation{operationTypeSwap,}
+		case i.val == "over":
+			return &langObjectOperation{operationTypeOver,}
+		case i.val == "rot":
+			return &langObjectOperation{operationTypeRot,}
+		case i.val == "-rot":
+			return &langObjectOperation{operationTypeReverseRot,}
+		case i.val == "nip":
+			return &langObjectOperation{operationTypeNip,}
+		case i.val == "tuck":
+			return &langObjectOperation{operationTypeTuck,}
+		case i.val == "pick":
+			return &langObjectOperation{operationTypePick,}
+		case i.val == "roll":
+			return &langObjectOperation{operationTypeRoll,}
+		case i.val == "2dup":
+			return &langObjectOperation{operationTypeTwoDup,}
+		case i.val == "2drop":
+			return &langObjectOperation{operationTypeTwoDrop,}
+		case i.val == "depth":
+			return &langObjectOperation{operationTypeDepth,}
+		case i.val == "dip":
+			return &langObjectOperation{operationTypeDip,}
+		case i.val == "keep":
+			return &langObjectOperation{operationTypeKeep,}
+		case i.val == "bi":
+			return &langObjectOperation{operationTypeBi,}
 		case i.val == "include":
 			return &langObjectOperation{operationTypeInclude,}
+		case i.val == "include-once":
+			return &langObjectOperation{operationTypeIncludeOnce,}
+		case i.val == "print":
+			return &langObjectOperation{operationTypePrint,}
+		case i.val == "println":
+			return &langObjectOperation{operationTypePrintLine,}
+		case i.val == "emit":
+			return &langObjectOperation{operationTypeEmit,}
+		case i.val == "read-line":
+			return &langObjectOperation{operationTypeReadLine,}
+		case i.val == "read-all":
+			return &langObjectOperation{operationTypeReadAll,}
+		case i.val == "read-file":
+			return &langObjectOperation{operationTypeReadFile,}
+		case i.val == "write-file":
+			return &langObjectOperation{operationTypeWriteFile,}
+		case i.val == "append-file":
+			return &langObjectOperation{operationTypeAppendFile,}
+		case i.val == "list-dir":
+			return &langObjectOperation{operationTypeListDir,}
+		case i.val == "exists?":
+			return &langObjectOperation{operationTypeExists,}
+		case i.val == "stat":
+			return &langObjectOperation{operationTypeStat,}
+		case i.val == "mkdir":
+			return &langObjectOperation{operationTypeMakeDir,}
+		case i.val == "remove":
+			return &langObjectOperation{operationTypeRemove,}
+		case i.val == "glob":
+			return &langObjectOperation{operationTypeGlob,}
+		case i.val == "import":
+			return &langObjectOperation{operationTypeImport,}
+		case i.val == "export":
+			return &langObjectOperation{operationTypeExport,}
+		case i.val == "format":
+			return &langObjectOperation{operationTypeFormat,}
+		case i.val == "length":
+			return &langObjectOperation{operationTypeListLength,}
+		case i.val == "reverse":
+			return &langObjectOperation{operationTypeListReverse,}
+		case i.val == "map":
+			return &langObjectOperation{operationTypeListMap,}
+		case i.val == "filter":
+			return &langObjectOperation{operationTypeListFilter,}
+		case i.val == "fold":
+			return &langObjectOperation{operationTypeListFold,}
+		case i.val == "reduce":
+			return &langObjectOperation{operationTypeListReduce,}
+		case i.val == "nth":
+			return &langObjectOperation{operationTypeListNth,}
+		case i.val == "append":
+			return &langObjectOperation{operationTypeListAppend,}
+		case i.val == "concat":
+			return &langObjectOperation{operationTypeListConcat,}
+		case i.val == "range":
+			return &langObjectOperation{operationTypeListRange,}
+		case i.val == "zip":
+			return &langObjectOperation{operationTypeListZip,}
+		case i.val == "sort":
+			return &langObjectOperation{operationTypeListSort,}
+		case i.val == "any?":
+			return &langObjectOperation{operationTypeListAny,}
+		case i.val == "all?":
+			return &langObjectOperation{operationTypeListAll,}
+		case i.val == "list->stack":
+			return &langObjectOperation{operationTypeListToStack,}
+		case i.val == "stack->list":
+			return &langObjectOperation{operationTypeStackToList,}
+		case i.val == "put":
+			return &langObjectOperation{operationTypeDictPut,}
+		case i.val == "get":
+			return &langObjectOperation{operationTypeGet,}
+		case i.val == "has?":
+			return &langObjectOperation{operationTypeDictHas,}
+		case i.val == "delete":
+			return &langObjectOperation{operationTypeDictDelete,}
+		case i.val == "keys":
+			return &langObjectOperation{operationTypeDictKeys,}
+		case i.val == "values":
+			return &langObjectOperation{operationTypeDictValues,}
+		case i.val == "size":
+			return &langObjectOperation{operationTypeDictSize,}
+		case i.val == "each":
+			return &langObjectOperation{operationTypeDictEach,}
+		case i.val == "array":
+			return &langObjectOperation{operationTypeArrayNew,}
+		case i.val == "set":
+			return &langObjectOperation{operationTypeArraySet,}
+		case i.val == "array-push":
+			return &langObjectOperation{operationTypeArrayPush,}
+		case i.val == "array-pop":
+			return &langObjectOperation{operationTypeArrayPop,}
+		case i.val == "slice":
+			return &langObjectOperation{operationTypeArraySlice,}
+		case i.val == "list->array":
+			return &langObjectOperation{operationTypeListToArray,}
+		case i.val == "array->list":
+			return &langObjectOperation{operationTypeArrayToList,}
+		case i.val == "string->number":
+			return &langObjectOperation{operationTypeStringToNumber,}
+		case i.val == "json-parse":
+			return &langObjectOperation{operationTypeJSONParse,}
+		case i.val == "json-stringify":
+			return &langObjectOperation{operationTypeJSONStringify,}
+		case i.val == "json-pretty":
+			return &langObjectOperation{operationTypeJSONPretty,}
+		case i.val == "csv-read":
+			return &langObjectOperation{operationTypeCSVRead,}
+		case i.val == "csv-read-file":
+			return &langObjectOperation{operationTypeCSVReadFile,}
+		case i.val == "csv-write":
+			return &langObjectOperation{operationTypeCSVWrite,}
+		case i.val == "re-match?":
+			return &langObjectOperation{operationTypeRegexMatch,}
+		case i.val == "re-find":
+			return &langObjectOperation{operationTypeRegexFind,}
+		case i.val == "re-find-all":
+			return &langObjectOperation{operationTypeRegexFindAll,}
+		case i.val == "re-replace":
+			return &langObjectOperation{operationTypeRegexReplace,}
+		case i.val == "re-split":
+			return &langObjectOperation{operationTypeRegexSplit,}
+		case i.val == "getenv":
+			return &langObjectOperation{operationTypeGetenv,}
+		case i.val == "setenv":
+			return &langObjectOperation{operationTypeSetenv,}
+		case i.val == "now":
+			return &langObjectOperation{operationTypeNow,}
+		case i.val == "time-format":
+			return &langObjectOperation{operationTypeTimeFormat,}
+		case i.val == "time-parse":
+			return &langObjectOperation{operationTypeTimeParse,}
+		case i.val == "sleep":
+			return &langObjectOperation{operationTypeSleep,}
+		case i.val == "exit":
+			return &langObjectOperation{operationTypeExit,}
+		case i.val == "args":
+			return &langObjectOperation{operationTypeArgs,}
+		case i.val == "exec":
+			return &langObjectOperation{operationTypeExec,}
+		case i.val == "exec-lines":
+			return &langObjectOperation{operationTypeExecLines,}
+		case i.val == "rand":
+			return &langObjectOperation{operationTypeRand,}
+		case i.val == "rand-int":
+			return &langObjectOperation{operationTypeRandInt,}
+		case i.val == "shuffle":
+			return &langObjectOperation{operationTypeShuffle,}
+		case i.val == "choice":
+			return &langObjectOperation{operationTypeChoice,}
+		case i.val == "seed":
+			return &langObjectOperation{operationTypeSeed,}
+		case i.val == "clone":
+			return &langObjectOperation{operationTypeClone,}
+		case i.val == "defrecord":
+			return &langObjectOperation{operationTypeDefineRecord,}
+		case i.val == "new":
+			return &langObjectOperation{operationTypeNewRecord,}
+		case i.val == "with":
+			return &langObjectOperation{operationTypeRecordWith,}
+		case i.val == "is?":
+			return &langObjectOperation{operationTypeRecordIs,}
+		case i.val == "type":
+			return &langObjectOperation{operationTypeTypeOf,}
+		case i.val == "number?":
+			return &langObjectOperation{operationTypeIsNumber,}
+		case i.val == "string?":
+			return &langObjectOperation{operationTypeIsString,}
+		case i.val == "boolean?":
+			return &langObjectOperation{operationTypeIsBoolean,}
+		case i.val == "block?":
+			return &langObjectOperation{operationTypeIsBlock,}
+		case i.val == "list?":
+			return &langObjectOperation{operationTypeIsList,}
+		case i.val == "ref?":
+			return &langObjectOperation{operationTypeIsReference,}
+		case i.val == "error?":
+			return &langObjectOperation{operationTypeIsError,}
+		case i.val == "symbol?":
+			return &langObjectOperation{operationTypeIsSymbol,}
+		case i.val == "dict?":
+			return &langObjectOperation{operationTypeIsDict,}
+		case i.val == "array?":
+			return &langObjectOperation{operationTypeIsArray,}
+		case i.val == "record?":
+			return &langObjectOperation{operationTypeIsRecord,}
 		default:
 			return &langObjectIdentifier{identifierDefault, i.val,}
 		}
@@ -77,41 +293,166 @@ func parseIdentifier(i item) langObject {
 
 func parseCodeBlock(p *parser) (*langObjectCodeBlock, error) {
 
+	code, lines, err := parseSequence(p, itemEOF, nil)
+
+	if err != nil {
+		return &langObjectCodeBlock{}, atLine(p.file, p.line, err)
+	}
+
+	return &langObjectCodeBlock{code,nil,nil,nil,lines,p.file,}, nil
+}
+
+/* Parses the contents of a stack effect declaration such as ( a b -- c ) */
+func parseStackEffect(declaration string) (*stackEffect, error) {
+
+	names := strings.Fields(declaration)
+	effect := &stackEffect{make([]string, 0), make([]string, 0),}
+	separators := 0
+
+	for _, name := range names {
+		switch {
+		case name == "--":
+			separators++
+		case separators == 0:
+			effect.inputs = append(effect.inputs, name)
+		default:
+			effect.outputs = append(effect.outputs, name)
+		}
+	}
+
+	if separators != 1 {
+		return nil, fmt.Errorf("Stack effect '(%s)' must contain exactly one '--'.", declaration)
+	}
+
+	return effect, nil
+}
+
+/* Parses the names in a parameter list such as |x y| */
+func parseParameters(declaration string) ([]string, error) {
+
+	params := strings.Fields(declaration)
+
+	for i, name := range params {
+		if strings.Trim(name, identifierRunes + "->") != "" || strings.IndexRune("0123456789", rune(name[0])) >= 0 {
+			return nil, fmt.Errorf("'%s' is not a valid parameter name.", name)
+		}
+
+		for _, other := range params[:i] {
+			if other == name {
+				return nil, fmt.Errorf("Parameter '%s' is named more than once.", name)
+			}
+		}
+	}
+
+	return params, nil
+}
+
+/*	Parses items up to the given closing item, which is itemEOF at the top level. When parsing the
+	contents of a code block, header is that block, and declarations at its start are recorded on it.
+	Along with the code, returns the line each item of it came from. */
+func parseSequence(p *parser, end itemType, header *langObjectCodeBlock) ([]langObject, []int, error) {
+
 	codeBlockItems := make([]langObject, 0)
+	lines := make([]int, 0)
 	
 	for i := range p.lexerItems {
+		p.line = i.line
+
 		switch {
 		case i.typ == itemError:
-			return &langObjectCodeBlock{}, fmt.Errorf("Lexer error: %s", i.val)
+			return nil, nil, fmt.Errorf("Lexer error: %s", i.val)
 		case i.typ == itemEOF:
-			if p.nestedLevel == 0 {
-				return &langObjectCodeBlock{codeBlockItems,nil}, nil
+			if end == itemEOF {
+				return codeBlockItems, lines, nil
 			} else {
-				return &langObjectCodeBlock{}, fmt.Errorf("Unexpected end of file.")
+				return nil, nil, fmt.Errorf("Unexpected end of file.")
 			}
 		case i.typ == itemOpenBlock:
-			p.nestedLevel++
-			codeBlock, err := parseCodeBlock(p)
+			codeBlock := &langObjectCodeBlock{}
+			code, codeLines, err := parseSequence(p, itemEndBlock, codeBlock)
 
 			if err != nil {
-				return &langObjectCodeBlock{}, err
+				return nil, nil, err
 			} else {
+				codeBlock.code = code
+				codeBlock.lines = codeLines
+				codeBlock.file = p.file
 				codeBlockItems = append(codeBlockItems, codeBlock)
 			}
-			
+		case i.typ == itemStackEffect:
+			if header == nil || len(codeBlockItems) > 0 || header.effect != nil {
+				return nil, nil, fmt.Errorf("A stack effect declaration must come at the beginning of a code block.")
+			}
+
+			effect, err := parseStackEffect(i.val)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if header.params != nil && len(header.params) != len(effect.inputs) {
+				return nil, nil, fmt.Errorf("Stack effect %s does not take the same number of items as the parameters %s.", effect.toString(), header.paramsString())
+			}
+
+			header.effect = effect
+		case i.typ == itemParameters:
+			if header == nil || len(codeBlockItems) > 0 || header.params != nil {
+				return nil, nil, fmt.Errorf("Parameters must come at the beginning of a code block.")
+			}
+
+			params, err := parseParameters(i.val)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if header.effect != nil && len(header.effect.inputs) != len(params) {
+				return nil, nil, fmt.Errorf("Stack effect %s does not take the same number of items as the parameters |%s|.", header.effect.toString(), strings.Join(params, " "))
+			}
+
+			header.params = params
 		case i.typ == itemEndBlock:
-			if p.nestedLevel < 1 {
-				return &langObjectCodeBlock{}, fmt.Errorf("Unexpected end of block.")
+			if end != itemEndBlock {
+				return nil, nil, fmt.Errorf("Unexpected end of block.")
 			} else {
-				p.nestedLevel--
+				return codeBlockItems, lines, nil
+			}
+		case i.typ == itemOpenList:
+			/* The elements of a list literal are evaluated in place, between two operations
+			   which mark the stack and collect what was pushed above the mark */
+			elements, elementLines, err := parseSequence(p, itemCloseList, nil)
+
+			if err != nil {
+				return nil, nil, err
+			}
 
-				return &langObjectCodeBlock{codeBlockItems,nil}, nil
+			codeBlockItems = append(codeBlockItems, &langObjectOperation{operationTypeListBegin,})
+			codeBlockItems = append(codeBlockItems, elements...)
+			codeBlockItems = append(codeBlockItems, &langObjectOperation{operationTypeListEnd,})
+			lines = append(append(append(lines, i.line), elementLines...), p.line)
+		case i.typ == itemOpenDict:
+			/* Dictionary literals work like list literals, and close with the same } as a block */
+			contents, contentLines, err := parseSequence(p, itemEndBlock, nil)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			codeBlockItems = append(codeBlockItems, &langObjectOperation{operationTypeDictBegin,})
+			codeBlockItems = append(codeBlockItems, contents...)
+			codeBlockItems = append(codeBlockItems, &langObjectOperation{operationTypeDictEnd,})
+			lines = append(append(append(lines, i.line), contentLines...), p.line)
+		case i.typ == itemCloseList:
+			if end != itemCloseList {
+				return nil, nil, fmt.Errorf("Unexpected end of list.")
+			} else {
+				return codeBlockItems, lines, nil
 			}
 		case i.typ == itemNumber:
 			number, err := strconv.ParseFloat(i.val, 64)
 
 			if err != nil {
-				return &langObjectCodeBlock{}, fmt.Errorf("Error parsing number: '%s'.", i.val)
+				return nil, nil, fmt.Errorf("Error parsing number: '%s'.", i.val)
 			} else {
 				codeBlockItems = append(codeBlockItems, &langObjectNumber{number,})
 			}
@@ -146,17 +487,21 @@ func parseCodeBlock(p *parser) (*langObjectCodeBlock, error) {
 			case ">=":
 				codeBlockItems = append(codeBlockItems, &langObjectOperation{operationTypeGreaterEquals,})
 			default:
-				return &langObjectCodeBlock{}, fmt.Errorf("Unknown condition type '%s'.", i.val)
+				return nil, nil, fmt.Errorf("Unknown condition type '%s'.", i.val)
 			}
 		case i.typ == itemEmptyList:
 			codeBlockItems = append(codeBlockItems, &langObjectList{true, nil, nil,})
 		case i.typ == itemCons:
 			codeBlockItems = append(codeBlockItems, &langObjectOperation{operationTypeCons,})
 		default:
-			return &langObjectCodeBlock{}, fmt.Errorf("Unknown lexer item type.")
+			return nil, nil, fmt.Errorf("Unknown lexer item type.")
+		}
+
+		for len(lines) < len(codeBlockItems) {
+			lines = append(lines, i.line)
 		}
 	}
 
-	return &langObjectCodeBlock{codeBlockItems,nil}, nil	
+	return codeBlockItems, lines, nil
 }
 
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+/* Running Other Programs */
+
+/* Pops the command to run: a list of strings, the program followed by its arguments */
+func (s *stack) popCommand() (*exec.Cmd, error) {
+	list, err1 := s.popList()
+
+	if err1 != nil {
+		return nil, err1
+	}
+
+	argv := make([]string, 0)
+
+	for _, obj := range list.elements() {
+		if obj.getType() != objectTypeString {
+			return nil, errors.New("Expected, but did not receive a list of strings to run.")
+		}
+
+		argv = append(argv, obj.(*langObjectString).val)
+	}
+
+	if len(argv) == 0 {
+		return nil, errors.New("Expected, but did not receive a program to run.")
+	}
+
+	return exec.Command(argv[0], argv[1:]...), nil
+}
+
+/*	The exit status of a command that has finished. A command that could not be started, or was
+	killed by a signal, has no status, so an error value is given instead. */
+func exitStatus(cmd *exec.Cmd, err error) langObject {
+	var exitErr *exec.ExitError
+
+	if err != nil && (!errors.As(err, &exitErr) || exitErr.ExitCode() < 0) {
+		return &langObjectError{err.Error(),}
+	}
+
+	return &langObjectNumber{float64(cmd.ProcessState.ExitCode()),}
+}
+
+func performProcessOperation(typ operationType, s *stack, v *variableScope, st *symbolTable, in *interpreter) error {
+
+	if !in.allows(capabilityProcess) {
+		return errors.New("Running other programs is not allowed.")
+	}
+
+	switch typ {
+	case operationTypeExec:
+		/* argv [stdin] -- stdout stderr status */
+		var stdin *string
+
+		if top, peekErr := s.peek(); peekErr == nil && top.getType() == objectTypeString {
+			s.pop()
+			stdin = &top.(*langObjectString).val
+		}
+
+		cmd, err1 := s.popCommand()
+
+		if err1 != nil {
+			return err1
+		}
+
+		var stdout, stderr bytes.Buffer
+
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		if stdin != nil {
+			cmd.Stdin = strings.NewReader(*stdin)
+		}
+
+		err2 := cmd.Run()
+
+		s.push(&langObjectString{stdout.String(),})
+		s.push(&langObjectString{stderr.String(),})
+
+		if cmd.ProcessState == nil {
+			s.push(&langObjectError{err2.Error(),})
+		} else {
+			s.push(exitStatus(cmd, err2))
+		}
+	case operationTypeExecLines:
+		/* argv block -- stderr status, calling the block with each line of output as it is written */
+		block, err1 := s.popCodeBlock()
+
+		if err1 != nil {
+			return err1
+		}
+
+		cmd, err2 := s.popCommand()
+
+		if err2 != nil {
+			return err2
+		}
+
+		var stderr bytes.Buffer
+
+		cmd.Stderr = &stderr
+
+		stdout, err3 := cmd.StdoutPipe()
+
+		if err3 != nil {
+			return err3
+		}
+
+		err4 := cmd.Start()
+
+		if err4 != nil {
+			s.push(&langObjectString{"",})
+			s.push(&langObjectError{err4.Error(),})
+			return nil
+		}
+
+		/* Lines are read whole, however long they are */
+		reader := bufio.NewReader(stdout)
+
+		for {
+			line, err5 := reader.ReadString('\n')
+
+			if err5 != nil && err5 != io.EOF {
+				cmd.Process.Kill()
+				cmd.Wait()
+
+				return err5
+			}
+
+			/* The last line may not end with a newline */
+			if line == "" && err5 == io.EOF {
+				break
+			}
+
+			s.push(&langObjectString{strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"),})
+
+			err6 := block.call(s, st, in)
+
+			if err6 != nil {
+				/* Stop the command rather than leave it blocked on a pipe nobody reads */
+				cmd.Process.Kill()
+				cmd.Wait()
+
+				return err6
+			}
+
+			if err5 == io.EOF {
+				break
+			}
+		}
+
+		err7 := cmd.Wait()
+
+		s.push(&langObjectString{stderr.String(),})
+		s.push(exitStatus(cmd, err7))
+	default:
+		return errors.New("Invalid process operation.")
+	}
+
+	return nil
+}
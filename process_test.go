@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+/* Lines longer than a bufio.Scanner allows, and a last line with no newline, all reach the block */
+func TestExecLinesLongLines(t *testing.T) {
+	s, v, st, in := newTestProgram()
+
+	code := `["sh" "-c" "yes x | head -c 2000000 | tr -d '\n'; echo; echo short; printf last"] { } exec-lines`
+
+	err := evalString(code, "", s, v, st, in, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{strings.Repeat("x", 1000000), "short", "last", "", "0.000000"}
+
+	if len(s.contents) != len(expected) {
+		t.Fatalf("Expected %d items on the stack, but there are %d", len(expected), len(s.contents))
+	}
+
+	for i := range expected {
+		if s.contents[i].toString() != expected[i] {
+			t.Errorf("Expected item %d to be %.20q, but it is %.20q", i, expected[i], s.contents[i].toString())
+		}
+	}
+}
+
+/* What the program writes to standard error is pushed after its output has been handled */
+func TestExecLinesStderr(t *testing.T) {
+	s, v, st, in := newTestProgram()
+
+	code := `["sh" "-c" "echo out; echo err >&2; exit 3"] { } exec-lines`
+
+	err := evalString(code, "", s, v, st, in, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectStrings(t, code, s.contents, "out", "err\n", "3.000000")
+}
+
+func TestExec(t *testing.T) {
+	tests := []struct {
+		code string
+		expected []string
+	}{
+		{`["echo" "hello"] exec`, []string{"hello\n", "", "0.000000"}},
+		{`["sh" "-c" "echo oops >&2"] exec`, []string{"", "oops\n", "0.000000"}},
+		{`["tr" "a-z" "A-Z"] "shout" exec`, []string{"SHOUT", "", "0.000000"}},
+		{`["sh" "-c" "printf out; exit 2"] exec`, []string{"out", "", "2.000000"}},
+	}
+
+	for _, test := range tests {
+		s, v, st, in := newTestProgram()
+
+		err := evalString(test.code, "", s, v, st, in, false)
+
+		if err != nil {
+			t.Errorf("Unable to run %s: %s", test.code, err)
+			continue
+		}
+
+		expectStrings(t, test.code, s.contents, test.expected...)
+	}
+}
+
+/* A program that cannot be started gives an error value for its status rather than stopping JSL */
+func TestExecMissingProgram(t *testing.T) {
+	for _, code := range []string{`["no-such-program-jsl"] exec`, `["no-such-program-jsl"] { } exec-lines`} {
+		s, v, st, in := newTestProgram()
+
+		err := evalString(code, "", s, v, st, in, false)
+
+		if err != nil {
+			t.Errorf("Unable to run %s: %s", code, err)
+			continue
+		}
+
+		if status, _ := s.pop(); status.getType() != objectTypeError {
+			t.Errorf("Expected %s to leave an error value, but it left %s", code, status.toString())
+		}
+	}
+}
+
+func TestExecSandboxed(t *testing.T) {
+	s, v, st, in := newTestProgram()
+	in.deny(capabilityProcess)
+
+	if err := evalString(`["true"] exec`, "", s, v, st, in, false); err == nil {
+		t.Errorf("Expected exec to fail without the process capability")
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+/* Random Numbers */
+
+/*	Each interpreter has its own source of random numbers, seeded from the clock, so that seed only
+	affects the program it is used in. */
+func newRandom(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+func defaultRandom() *rand.Rand {
+	return newRandom(time.Now().UnixNano())
+}
+
+func wholeNumber(n float64, what string) (int64, error) {
+	if n != math.Trunc(n) {
+		return 0, fmt.Errorf("Expected a whole number for %s, but received %v.", what, n)
+	}
+
+	/* -2^63 and 2^63 are exact as floats, so the conversion below cannot overflow */
+	if n < math.MinInt64 || n >= -math.MinInt64 {
+		return 0, fmt.Errorf("Expected a whole number for %s, but received %v, which is too large.", what, n)
+	}
+
+	return int64(n), nil
+}
+
+/* Like wholeNumber, but the number must also be between low and high, inclusive */
+func wholeNumberIn(n float64, low int64, high int64, what string) (int64, error) {
+	whole, err := wholeNumber(n, what)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if whole < low || whole > high {
+		return 0, fmt.Errorf("Expected a whole number from %d to %d for %s, but received %v.", low, high, what, n)
+	}
+
+	return whole, nil
+}
+
+func performRandomOperation(typ operationType, s *stack, v *variableScope, st *symbolTable, in *interpreter) error {
+
+	switch typ {
+	case operationTypeRand:
+		/* -- n, where 0 <= n < 1 */
+		s.push(&langObjectNumber{in.random.Float64(),})
+	case operationTypeRandInt:
+		/* low high -- n, where low <= n <= high */
+		highNumber, err1 := s.popNumber()
+
+		if err1 != nil {
+			return err1
+		}
+
+		lowNumber, err2 := s.popNumber()
+
+		if err2 != nil {
+			return err2
+		}
+
+		high, err3 := wholeNumber(highNumber, "the top of the range")
+
+		if err3 != nil {
+			return err3
+		}
+
+		low, err4 := wholeNumber(lowNumber, "the bottom of the range")
+
+		if err4 != nil {
+			return err4
+		}
+
+		if low > high {
+			return fmt.Errorf("The range %d to %d is empty.", low, high)
+		}
+
+		/* A range wider than an int64 can count overflows, leaving a span of zero or less */
+		span := high - low + 1
+
+		if span <= 0 {
+			return fmt.Errorf("The range %d to %d is too large.", low, high)
+		}
+
+		s.push(&langObjectNumber{float64(low + in.random.Int63n(span)),})
+	case operationTypeShuffle:
+		obj, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		switch obj.getType() {
+		case objectTypeList:
+			/* Lists cannot be changed, so a shuffled copy is made */
+			elements := append([]langObject{}, obj.(*langObjectList).elements()...)
+
+			in.random.Shuffle(len(elements), func(i, j int) {
+				elements[i], elements[j] = elements[j], elements[i]
+			})
+
+			s.push(newList(elements))
+		case objectTypeArray:
+			/* ... while arrays are shuffled in place */
+			elements := obj.(*langObjectArray).elements
+
+			in.random.Shuffle(len(elements), func(i, j int) {
+				elements[i], elements[j] = elements[j], elements[i]
+			})
+
+			s.push(obj)
+		default:
+			return errors.New("Expected, but did not receive a list or an array.")
+		}
+	case operationTypeChoice:
+		obj, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		var elements []langObject
+
+		switch obj.getType() {
+		case objectTypeList:
+			elements = obj.(*langObjectList).elements()
+		case objectTypeArray:
+			elements = obj.(*langObjectArray).elements
+		default:
+			return errors.New("Expected, but did not receive a list or an array.")
+		}
+
+		if len(elements) == 0 {
+			return errors.New("Unable to choose from an empty list.")
+		}
+
+		s.push(elements[in.random.Intn(len(elements))])
+	case operationTypeSeed:
+		n, err1 := s.popNumber()
+
+		if err1 != nil {
+			return err1
+		}
+
+		seed, err2 := wholeNumber(n, "the seed")
+
+		if err2 != nil {
+			return err2
+		}
+
+		in.random = newRandom(seed)
+	default:
+		return errors.New("Invalid random number operation.")
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestRandIntRanges(t *testing.T) {
+	tests := []struct {
+		code string
+		fails bool
+	}{
+		{"1 6 rand-int", false},
+		{"3 3 rand-int", false},
+		{"-4000000000000000000 4000000000000000000 rand-int", false},
+		{"6 1 rand-int", true},
+		{"1.5 6 rand-int", true},
+		{"-5000000000000000000 5000000000000000000 rand-int", true},
+		{"0 99999999999999999999 rand-int", true},
+		{"99999999999999999999 seed", true},
+	}
+
+	for _, test := range tests {
+		s, v, st, in := newTestProgram()
+
+		err := evalString(test.code, "", s, v, st, in, false)
+
+		if test.fails && err == nil {
+			t.Errorf("Expected %s to fail", test.code)
+		} else if !test.fails && err != nil {
+			t.Errorf("Unable to run %s: %s", test.code, err)
+		}
+	}
+}
+
+/* The same seed gives the same numbers, and seeding one interpreter does not affect another */
+func TestSeed(t *testing.T) {
+	code := "42 seed 1 1000000 rand-int rand [1 2 3 4 5] shuffle list->stack"
+
+	s1, v1, st1, in1 := newTestProgram()
+	s2, v2, st2, in2 := newTestProgram()
+
+	for _, program := range []struct {
+		s *stack
+		v *variableScope
+		st *symbolTable
+		in *interpreter
+	}{{s1, v1, st1, in1}, {s2, v2, st2, in2}} {
+		err := evalString(code, "", program.s, program.v, program.st, program.in, false)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := range s1.contents {
+		if s1.contents[i].toString() != s2.contents[i].toString() {
+			t.Errorf("Expected the same results from the same seed, but received %s and %s", s1.contents[i].toString(), s2.contents[i].toString())
+		}
+	}
+}
+
+/* Drawing numbers in one interpreter does not change those another one draws */
+func TestSeedIndependent(t *testing.T) {
+	s1, v1, st1, in1 := newTestProgram()
+	s2, v2, st2, in2 := newTestProgram()
+
+	for _, run := range []struct {
+		code string
+		s *stack
+		v *variableScope
+		st *symbolTable
+		in *interpreter
+	}{
+		{"7 seed", s1, v1, st1, in1},
+		{"7 seed", s2, v2, st2, in2},
+		{"rand rand rand", s1, v1, st1, in1},
+		{"rand", s2, v2, st2, in2},
+	} {
+		err := evalString(run.code, "", run.s, run.v, run.st, run.in, false)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if s1.contents[0].toString() != s2.contents[0].toString() {
+		t.Errorf("Expected the first number after the same seed to be %s, but received %s", s1.contents[0].toString(), s2.contents[0].toString())
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/* Record Types */
+
+/*	A record type is created by defrecord and bound to a variable named after the type. Record
+	types compare by identity, so two types with the same name and fields are still different. */
+type langObjectRecordType struct {
+	name string
+	fields []string
+}
+
+func (l *langObjectRecordType) getType() langObjectType {
+	return objectTypeRecordType
+}
+
+func (l *langObjectRecordType) getValue() interface{} {
+	return l
+}
+
+func (l *langObjectRecordType) setValue(typ interface{}) {
+	*l = *(typ.(*langObjectRecordType))
+}
+
+func (l *langObjectRecordType) toString() string {
+	return fmt.Sprintf("<Record: %s %s>", l.name, strings.Join(l.fields, " "))
+}
+
+func (l *langObjectRecordType) copy() langObject {
+	return l
+}
+
+func (l *langObjectRecordType) equals(o langObject) (bool, error) {
+	switch o.getType() {
+	case objectTypeRecordType:
+		return (l == o.(*langObjectRecordType)), nil
+	default:
+		return false, nil
+	}
+}
+
+func (l *langObjectRecordType) greaterThan(o langObject) (bool, error) {
+	return unordered(l, o, "greater than", "record type")
+}
+
+func (l *langObjectRecordType) lessThan(o langObject) (bool, error) {
+	return unordered(l, o, "less than", "record type")
+}
+
+func (l *langObjectRecordType) fieldIndex(field langObject) (int, error) {
+	name, ok := symbolName(field)
+
+	if ok == false {
+		return 0, errors.New("Expected, but did not receive a field name.")
+	}
+
+	for i, f := range l.fields {
+		if f == name {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("Record %s has no field '%s'.", l.name, name)
+}
+
+/* Records */
+
+/* Records are immutable; with returns a new record rather than changing the old one */
+type langObjectRecord struct {
+	typ *langObjectRecordType
+	values []langObject
+}
+
+func (l *langObjectRecord) getType() langObjectType {
+	return objectTypeRecord
+}
+
+func (l *langObjectRecord) getValue() interface{} {
+	return l.values
+}
+
+func (l *langObjectRecord) setValue(values interface{}) {
+	l.values = values.([]langObject)
+}
+
+func (l *langObjectRecord) toString() string {
+	fields := make([]string, 0)
+
+	for i, name := range l.typ.fields {
+		fields = append(fields, "." + name + " " + literalString(l.values[i]))
+	}
+
+	return fmt.Sprintf("<%s %s>", l.typ.name, strings.Join(fields, " "))
+}
+
+func (l *langObjectRecord) copy() langObject {
+	return l
+}
+
+/* Records are equal if they have the same type and equal fields */
+func (l *langObjectRecord) equals(o langObject) (bool, error) {
+	if o.getType() != objectTypeRecord || o.(*langObjectRecord).typ != l.typ {
+		return false, nil
+	}
+
+	return newList(l.values).equals(newList(o.(*langObjectRecord).values))
+}
+
+func (l *langObjectRecord) greaterThan(o langObject) (bool, error) {
+	return unordered(l, o, "greater than", "record")
+}
+
+func (l *langObjectRecord) lessThan(o langObject) (bool, error) {
+	return unordered(l, o, "less than", "record")
+}
+
+func (l *langObjectRecord) field(name langObject) (langObject, error) {
+	i, err := l.typ.fieldIndex(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return l.values[i], nil
+}
+
+func performRecordOperation(typ operationType, s *stack, v *variableScope, st *symbolTable) error {
+
+	switch typ {
+	case operationTypeDefineRecord:
+		fieldList, err1 := s.popList()
+
+		if err1 != nil {
+			return err1
+		}
+
+		nameObj, err2 := s.pop()
+
+		if err2 != nil {
+			return err2
+		}
+
+		name, ok := symbolName(nameObj)
+
+		if ok == false {
+			return errors.New("Expected, but did not receive a record name.")
+		}
+
+		recordType := &langObjectRecordType{name, make([]string, 0),}
+
+		for _, fieldObj := range fieldList.elements() {
+			field, fieldOk := symbolName(fieldObj)
+
+			if fieldOk == false {
+				return errors.New("Record fields must be symbols, such as .age.")
+			}
+
+			for _, f := range recordType.fields {
+				if f == field {
+					return fmt.Errorf("Record %s has more than one field named '%s'.", name, field)
+				}
+			}
+
+			recordType.fields = append(recordType.fields, field)
+		}
+
+		/* The type is bound to a variable with the record's name, just as if asn had been used */
+		s.push(recordType)
+		s.push(&langObjectIdentifier{identifierReference, name,})
+
+		return performAssign(operationTypeAssign, s, v, st)
+	case operationTypeNewRecord:
+		typeObj, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		if typeObj.getType() != objectTypeRecordType {
+			return errors.New("Expected, but did not receive a record type.")
+		}
+
+		recordType := typeObj.(*langObjectRecordType)
+
+		values := make([]langObject, len(recordType.fields))
+
+		for i := len(values) - 1; i >= 0; i-- {
+			value, err2 := s.pop()
+
+			if err2 != nil {
+				return err2
+			}
+
+			values[i] = value
+		}
+
+		s.push(&langObjectRecord{recordType, values,})
+	case operationTypeGet:
+		field, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		recordObj, err2 := s.pop()
+
+		if err2 != nil {
+			return err2
+		}
+
+		if recordObj.getType() != objectTypeRecord {
+			return errors.New("Expected, but did not receive a record.")
+		}
+
+		value, err3 := recordObj.(*langObjectRecord).field(field)
+
+		if err3 != nil {
+			return err3
+		}
+
+		s.push(value)
+	case operationTypeRecordWith:
+		value, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		field, err2 := s.pop()
+
+		if err2 != nil {
+			return err2
+		}
+
+		recordObj, err3 := s.pop()
+
+		if err3 != nil {
+			return err3
+		}
+
+		if recordObj.getType() != objectTypeRecord {
+			return errors.New("Expected, but did not receive a record.")
+		}
+
+		record := recordObj.(*langObjectRecord)
+
+		i, err4 := record.typ.fieldIndex(field)
+
+		if err4 != nil {
+			return err4
+		}
+
+		values := append([]langObject{}, record.values...)
+		values[i] = value
+
+		s.push(&langObjectRecord{record.typ, values,})
+	case operationTypeRecordIs:
+		typeObj, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		if typeObj.getType() != objectTypeRecordType {
+			return errors.New("Expected, but did not receive a record type.")
+		}
+
+		obj, err2 := s.pop()
+
+		if err2 != nil {
+			return err2
+		}
+
+		s.push(&langObjectBoolean{obj.getType() == objectTypeRecord && obj.(*langObjectRecord).typ == typeObj.(*langObjectRecordType),})
+	default:
+		return errors.New("Invalid record operation.")
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"container/list"
+	"errors"
+	"regexp"
+)
+
+/* Regular Expressions */
+
+/* The most compiled patterns the interpreter keeps */
+const regexpCacheSize = 64
+
+/*	Compiled patterns, kept so that a pattern used in a loop is only compiled once. When the cache is
+	full, the pattern used least recently is dropped. */
+type regexpCache struct {
+	entries map[string]*list.Element
+	recent *list.List
+}
+
+type regexpCacheEntry struct {
+	pattern string
+	re *regexp.Regexp
+}
+
+func newRegexpCache() *regexpCache {
+	return &regexpCache{make(map[string]*list.Element), list.New(),}
+}
+
+/* Patterns use Go's regexp syntax */
+func (in *interpreter) compileRegexp(pattern string) (*regexp.Regexp, error) {
+	cache := in.regexps
+
+	if element, ok := cache.entries[pattern]; ok {
+		cache.recent.MoveToFront(element)
+		return element.Value.(*regexpCacheEntry).re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cache.recent.Len() >= regexpCacheSize {
+		oldest := cache.recent.Back()
+		cache.recent.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*regexpCacheEntry).pattern)
+	}
+
+	cache.entries[pattern] = cache.recent.PushFront(&regexpCacheEntry{pattern, re,})
+
+	return re, nil
+}
+
+func performRegexOperation(typ operationType, s *stack, v *variableScope, st *symbolTable, in *interpreter) error {
+
+	/* re-replace has its replacement above the pattern */
+	var replacement langObject
+
+	if typ == operationTypeRegexReplace {
+		obj, err1 := s.pop()
+
+		if err1 != nil {
+			return err1
+		}
+
+		if obj.getType() != objectTypeString && obj.getType() != objectTypeCodeBlock {
+			return errors.New("Expected, but did not receive a replacement string or code block.")
+		}
+
+		replacement = obj
+	}
+
+	pattern, err2 := s.popString("a pattern")
+
+	if err2 != nil {
+		return err2
+	}
+
+	re, err3 := in.compileRegexp(pattern)
+
+	if err3 != nil {
+		return err3
+	}
+
+	text, err4 := s.popString("a string to match against")
+
+	if err4 != nil {
+		return err4
+	}
+
+	switch typ {
+	case operationTypeRegexMatch:
+		s.push(&langObjectBoolean{re.MatchString(text),})
+	case operationTypeRegexFind:
+		/* The whole match, followed by the capture groups */
+		groups := re.FindStringSubmatch(text)
+
+		if groups == nil {
+			s.push(intern("None"))
+		} else {
+			s.push(stringList(groups))
+		}
+	case operationTypeRegexFindAll:
+		matches := make([]langObject, 0)
+
+		for _, groups := range re.FindAllStringSubmatch(text, -1) {
+			matches = append(matches, stringList(groups))
+		}
+
+		s.push(newList(matches))
+	case operationTypeRegexReplace:
+		if replacement.getType() == objectTypeString {
+			/* $1 and ${name} in the replacement refer to capture groups */
+			s.push(&langObjectString{re.ReplaceAllString(text, replacement.(*langObjectString).val),})
+			break
+		}
+
+		/* The block is called with each match, and leaves the string to replace it with */
+		block := replacement.(*langObjectCodeBlock)
+		var blockErr error
+
+		result := re.ReplaceAllStringFunc(text, func(match string) string {
+			if blockErr != nil {
+				return match
+			}
+
+			obj, err5 := callForResult(block, s, st, in, &langObjectString{match,})
+
+			if err5 != nil {
+				blockErr = err5
+				return match
+			}
+
+			if obj.getType() != objectTypeString {
+				blockErr = errors.New("Expected the code block to leave a string on the stack.")
+				return match
+			}
+
+			return obj.(*langObjectString).val
+		})
+
+		if blockErr != nil {
+			return blockErr
+		}
+
+		s.push(&langObjectString{result,})
+	case operationTypeRegexSplit:
+		s.push(stringList(re.Split(text, -1)))
+	default:
+		return errors.New("Invalid regular expression operation.")
+	}
+
+	return nil
+}
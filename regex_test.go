@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+/* Runs each piece of code and compares what it leaves on top of the stack with the expected value */
+func expectResults(t *testing.T, tests map[string]langObject) {
+	for code, expected := range tests {
+		s, v, st, in := newTestProgram()
+
+		err := evalString(code, "", s, v, st, in, false)
+
+		if err != nil {
+			t.Errorf("Unable to run %s: %s", code, err)
+			continue
+		}
+
+		result, _ := s.pop()
+
+		if equal, _ := result.equals(expected); !equal {
+			t.Errorf("Expected %s to leave %s, but it left %s", code, literalString(expected), literalString(result))
+		}
+	}
+}
+
+func TestRegexFind(t *testing.T) {
+	expectResults(t, map[string]langObject{
+		`"width 30 height 40" "(\w+) (\d+)" re-find`: stringList([]string{"width 30", "width", "30"}),
+		`"abc" "\d" re-find`: intern("None"),
+		`"a1b22" "\d+" re-find-all`: newList([]langObject{stringList([]string{"1"}), stringList([]string{"22"})}),
+		`"a1b22" "x" re-find-all length`: &langObjectNumber{0,},
+	})
+}
+
+func TestRegexReplace(t *testing.T) {
+	expectResults(t, map[string]langObject{
+		`"a1b22" "\d+" "#" re-replace`: &langObjectString{"a#b#",},
+		`"John Smith" "(\w+) (\w+)" "$2, $1" re-replace`: &langObjectString{"Smith, John",},
+		`"a1b22" "\d+" { "<" swap + ">" + } re-replace`: &langObjectString{"a<1>b<22>",},
+		`"x y" "\w" { dup + } re-replace`: &langObjectString{"xx yy",},
+		`"none" "\d" { pop "!" } re-replace`: &langObjectString{"none",},
+	})
+
+	s, v, st, in := newTestProgram()
+
+	if err := evalString(`"a1" "\d" { pop 5 } re-replace`, "", s, v, st, in, false); err == nil {
+		t.Errorf("Expected a block that leaves a number to be an error")
+	}
+}
+
+func TestRegexSplit(t *testing.T) {
+	expectResults(t, map[string]langObject{
+		`"a, b,c" ",\s*" re-split`: stringList([]string{"a", "b", "c"}),
+		`"abc" "," re-split`: stringList([]string{"abc"}),
+		`",a," "," re-split`: stringList([]string{"", "a", ""}),
+	})
+}
+
+/* The cache never holds more than its size, and drops the pattern used least recently */
+func TestRegexCache(t *testing.T) {
+	_, _, _, in := newTestProgram()
+
+	for i := 0; i < regexpCacheSize * 2; i++ {
+		if _, err := in.compileRegexp(fmt.Sprintf("a{%d}", i)); err != nil {
+			t.Fatal(err)
+		}
+
+		/* Keep using the first pattern, so that it is never the oldest */
+		in.compileRegexp("a{0}")
+	}
+
+	if len(in.regexps.entries) != regexpCacheSize || in.regexps.recent.Len() != regexpCacheSize {
+		t.Errorf("Expected the cache to hold %d patterns, but it holds %d", regexpCacheSize, len(in.regexps.entries))
+	}
+
+	if _, ok := in.regexps.entries["a{0}"]; !ok {
+		t.Errorf("Expected the pattern used most recently to be kept")
+	}
+
+	if _, ok := in.regexps.entries["a{1}"]; ok {
+		t.Errorf("Expected the pattern used least recently to be dropped")
+	}
+}
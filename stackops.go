@@ -0,0 +1,325 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+/* Stack Manipulation */
+
+/* Whenever a reference is copied on the stack, the symbol table must know about it */
+func retain(obj langObject, st *symbolTable) error {
+	if obj.getType() == objectTypeReference {
+		return st.incReference(obj.(*langObjectReference).key)
+	}
+
+	return nil
+}
+
+/* ... and whenever one is dropped */
+func release(obj langObject, st *symbolTable) error {
+	if obj.getType() == objectTypeReference {
+		return st.decReference(obj.(*langObjectReference).key)
+	}
+
+	return nil
+}
+
+/* Empties the stack, dropping every item on it the way pop and drop do */
+func (s *stack) releaseAll(st *symbolTable) error {
+	for len(s.contents) > 0 {
+		obj, _ := s.pop()
+
+		err := release(obj, st)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.clear()
+}
+
+/* Makes sure there are at least n items on the stack, so that operations fail before changing it */
+func (s *stack) require(n int) error {
+	if len(s.contents) < n {
+		return errors.New("Stack underflow")
+	}
+
+	return nil
+}
+
+/* Pops the depth used by pick and roll, which counts down from the top of the stack, starting at 0 */
+func (s *stack) popDepth() (int, error) {
+	n, err := s.popNumber()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if n != math.Trunc(n) || n < 0 {
+		return 0, fmt.Errorf("Expected a whole number for the stack depth, but received %v.", n)
+	}
+
+	if int(n) >= len(s.contents) {
+		return 0, fmt.Errorf("Unable to reach item %d, as there are only %d items on the stack.", int(n), len(s.contents))
+	}
+
+	return int(n), nil
+}
+
+func performStackOperation(typ operationType, s *stack, v *variableScope, st *symbolTable, in *interpreter) error {
+
+	top := len(s.contents) - 1
+
+	switch typ {
+	case operationTypeSwap:
+		/* a b -- b a */
+		err1 := s.require(2)
+
+		if err1 != nil {
+			return err1
+		}
+
+		s.contents[top], s.contents[top - 1] = s.contents[top - 1], s.contents[top]
+	case operationTypeOver:
+		/* a b -- a b a */
+		err1 := s.require(2)
+
+		if err1 != nil {
+			return err1
+		}
+
+		obj := s.contents[top - 1]
+
+		err2 := retain(obj, st)
+
+		if err2 != nil {
+			return err2
+		}
+
+		s.push(obj.copy())
+	case operationTypeRot:
+		/* a b c -- b c a */
+		err1 := s.require(3)
+
+		if err1 != nil {
+			return err1
+		}
+
+		a := s.contents[top - 2]
+		copy(s.contents[top - 2:], s.contents[top - 1:])
+		s.contents[top] = a
+	case operationTypeReverseRot:
+		/* a b c -- c a b */
+		err1 := s.require(3)
+
+		if err1 != nil {
+			return err1
+		}
+
+		c := s.contents[top]
+		copy(s.contents[top - 1:], s.contents[top - 2:top])
+		s.contents[top - 2] = c
+	case operationTypeNip:
+		/* a b -- b */
+		err1 := s.require(2)
+
+		if err1 != nil {
+			return err1
+		}
+
+		err2 := release(s.contents[top - 1], st)
+
+		if err2 != nil {
+			return err2
+		}
+
+		s.contents[top - 1] = s.contents[top]
+		s.contents = s.contents[:top]
+	case operationTypeTuck:
+		/* a b -- b a b */
+		err1 := s.require(2)
+
+		if err1 != nil {
+			return err1
+		}
+
+		b := s.contents[top]
+
+		err2 := retain(b, st)
+
+		if err2 != nil {
+			return err2
+		}
+
+		s.contents[top] = s.contents[top - 1]
+		s.contents[top - 1] = b.copy()
+		s.push(b)
+	case operationTypePick:
+		/* xn ... x0 n -- xn ... x0 xn */
+		n, err1 := s.popDepth()
+
+		if err1 != nil {
+			return err1
+		}
+
+		obj := s.contents[len(s.contents) - 1 - n]
+
+		err2 := retain(obj, st)
+
+		if err2 != nil {
+			return err2
+		}
+
+		s.push(obj.copy())
+	case operationTypeRoll:
+		/* xn ... x0 n -- xn-1 ... x0 xn */
+		n, err1 := s.popDepth()
+
+		if err1 != nil {
+			return err1
+		}
+
+		i := len(s.contents) - 1 - n
+		obj := s.contents[i]
+
+		copy(s.contents[i:], s.contents[i + 1:])
+		s.contents[len(s.contents) - 1] = obj
+	case operationTypeTwoDup:
+		/* a b -- a b a b */
+		err1 := s.require(2)
+
+		if err1 != nil {
+			return err1
+		}
+
+		a, b := s.contents[top - 1], s.contents[top]
+
+		err2 := retain(a, st)
+
+		if err2 != nil {
+			return err2
+		}
+
+		err3 := retain(b, st)
+
+		if err3 != nil {
+			return err3
+		}
+
+		s.push(a.copy())
+		s.push(b.copy())
+	case operationTypeTwoDrop:
+		/* a b -- */
+		err1 := s.require(2)
+
+		if err1 != nil {
+			return err1
+		}
+
+		for i := 0; i < 2; i++ {
+			obj, _ := s.pop()
+
+			err2 := release(obj, st)
+
+			if err2 != nil {
+				return err2
+			}
+		}
+	case operationTypeDepth:
+		s.push(&langObjectNumber{float64(len(s.contents)),})
+	case operationTypeDip:
+		/* Runs the block with the item beneath it out of the way: a block -- ... a */
+		block, err1 := s.popCodeBlock()
+
+		if err1 != nil {
+			return err1
+		}
+
+		obj, err2 := s.pop()
+
+		if err2 != nil {
+			return err2
+		}
+
+		err3 := block.call(s, st, in)
+
+		if err3 != nil {
+			return err3
+		}
+
+		s.push(obj)
+	case operationTypeKeep:
+		/* Runs the block on a copy of the item beneath it, and restores the item: a block -- ... a */
+		block, err1 := s.popCodeBlock()
+
+		if err1 != nil {
+			return err1
+		}
+
+		obj, err2 := s.peek()
+
+		if err2 != nil {
+			return err2
+		}
+
+		err3 := retain(obj, st)
+
+		if err3 != nil {
+			return err3
+		}
+
+		err4 := block.call(s, st, in)
+
+		if err4 != nil {
+			return err4
+		}
+
+		s.push(obj.copy())
+	case operationTypeBi:
+		/* Runs both blocks on the same item: a block1 block2 -- ... */
+		second, err1 := s.popCodeBlock()
+
+		if err1 != nil {
+			return err1
+		}
+
+		first, err2 := s.popCodeBlock()
+
+		if err2 != nil {
+			return err2
+		}
+
+		obj, err3 := s.peek()
+
+		if err3 != nil {
+			return err3
+		}
+
+		err4 := retain(obj, st)
+
+		if err4 != nil {
+			return err4
+		}
+
+		err5 := first.call(s, st, in)
+
+		if err5 != nil {
+			return err5
+		}
+
+		s.push(obj.copy())
+
+		err6 := second.call(s, st, in)
+
+		if err6 != nil {
+			return err6
+		}
+	default:
+		return errors.New("Invalid stack operation.")
+	}
+
+	return nil
+}
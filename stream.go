@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+/*	Takes the BEGIN { ... } and END { ... } blocks out of the code given to -n, as in awk, and
+	returns them in the order they were written. */
+func extractBlocks(main *langObjectCodeBlock) ([]*langObjectCodeBlock, []*langObjectCodeBlock) {
+	begin := make([]*langObjectCodeBlock, 0)
+	end := make([]*langObjectCodeBlock, 0)
+
+	code := make([]langObject, 0)
+	lines := make([]int, 0)
+
+	for i := 0; i < len(main.code); i++ {
+		if ident, ok := main.code[i].(*langObjectIdentifier); ok && ident.typ == identifierDefault && i + 1 < len(main.code) {
+			if block, isBlock := main.code[i + 1].(*langObjectCodeBlock); isBlock && (ident.name == "BEGIN" || ident.name == "END") {
+				if ident.name == "BEGIN" {
+					begin = append(begin, block)
+				} else {
+					end = append(end, block)
+				}
+
+				i++
+				continue
+			}
+		}
+
+		code = append(code, main.code[i])
+
+		if i < len(main.lines) {
+			lines = append(lines, main.lines[i])
+		}
+	}
+
+	main.code = code
+	main.lines = lines
+
+	return begin, end
+}
+
+/* Runs BEGIN and END blocks in the program's own scope, so that the variables they set last */
+func runBlocks(blocks []*langObjectCodeBlock, s *stack, v *variableScope, st *symbolTable, in *interpreter) error {
+	for _, block := range blocks {
+		block.parentScope = v
+
+		err := block.exec(s, v, st, in, false)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* Reports an error that stopped the program, and returns the exit status it leads to */
+func (in *interpreter) reportError(where string, err error) int {
+	if status, ok := in.exitOn(err); ok {
+		return status
+	}
+
+	fmt.Fprintf(os.Stderr, "Error%s: %s\n", where, err.Error())
+
+	return 1
+}
+
+/*	Runs code once for each line of input, in the manner of awk. Each line is pushed onto an empty
+	stack before the code runs, while variables last from one line to the next. Code in BEGIN and
+	END blocks, and in begin and end if given, runs before the first line and after the last. If
+	printTop is set, the top of the stack is printed after each line. Returns the exit status. */
+func processLines(code string, begin string, end string, printTop bool, s *stack, v *variableScope, st *symbolTable, in *interpreter) int {
+
+	main, err1 := compile(code, "")
+
+	if err1 != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err1.Error())
+		return 2
+	}
+
+	beginBlocks, endBlocks := extractBlocks(main)
+
+	if begin != "" {
+		err2 := evalString(begin, "", s, v, st, in, false)
+
+		if err2 != nil {
+			return in.reportError(" in -begin", err2)
+		}
+	}
+
+	err3 := runBlocks(beginBlocks, s, v, st, in)
+
+	if err3 != nil {
+		return in.reportError(" in BEGIN", err3)
+	}
+
+	for lineNumber := 1; ; lineNumber++ {
+		line, ok, err4 := in.readLine()
+
+		if err4 != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err4.Error())
+			return 1
+		}
+
+		if !ok {
+			break
+		}
+
+		/* Whatever the last line left behind is dropped, so that the symbol table can free it */
+		err5 := s.releaseAll(st)
+
+		if err5 != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err5.Error())
+			return 1
+		}
+
+		s.push(&langObjectString{line,})
+
+		err6 := main.exec(s, v, st, in, false)
+
+		if err6 != nil {
+			return in.reportError(fmt.Sprintf(" on line %d of the input", lineNumber), err6)
+		}
+
+		if printTop && len(s.contents) > 0 {
+			_, err7 := io.WriteString(in.output, s.contents[len(s.contents) - 1].toString() + "\n")
+
+			if err7 != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err7.Error())
+				return 1
+			}
+		}
+	}
+
+	err8 := s.releaseAll(st)
+
+	if err8 != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err8.Error())
+		return 1
+	}
+
+	err9 := runBlocks(endBlocks, s, v, st, in)
+
+	if err9 != nil {
+		return in.reportError(" in END", err9)
+	}
+
+	if end != "" {
+		err10 := evalString(end, "", s, v, st, in, false)
+
+		if err10 != nil {
+			return in.reportError(" in -end", err10)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"sync"
+)
+
+/* Symbols */
+
+/*	A symbol is written as a name prefixed with a period, like .None or .Mon. Symbols never refer
+	to variables. They are interned, so there is only ever one symbol with a given name, and two
+	symbols are equal exactly when they are the same object. */
+type langObjectSymbol struct {
+	name string
+}
+
+var internedSymbols = struct {
+	sync.Mutex
+	symbols map[string]*langObjectSymbol
+}{symbols: make(map[string]*langObjectSymbol),}
+
+func intern(name string) *langObjectSymbol {
+	internedSymbols.Lock()
+	defer internedSymbols.Unlock()
+
+	symbol, ok := internedSymbols.symbols[name]
+
+	if ok == false {
+		symbol = &langObjectSymbol{name,}
+		internedSymbols.symbols[name] = symbol
+	}
+
+	return symbol
+}
+
+/* Returns the name of a symbol, such as age for .age */
+func symbolName(obj langObject) (string, bool) {
+	if obj.getType() != objectTypeSymbol {
+		return "", false
+	}
+
+	return obj.(*langObjectSymbol).name, true
+}
+
+func (l *langObjectSymbol) getType() langObjectType {
+	return objectTypeSymbol
+}
+
+func (l *langObjectSymbol) getValue() interface{} {
+	return l.name
+}
+
+/* Symbols are shared by everything that uses them, so they are never changed */
+func (l *langObjectSymbol) setValue(name interface{}) {
+}
+
+func (l *langObjectSymbol) toString() string {
+	return "." + l.name
+}
+
+func (l *langObjectSymbol) copy() langObject {
+	return l
+}
+
+func (l *langObjectSymbol) equals(o langObject) (bool, error) {
+	return (l == o), nil
+}
+
+/* Symbols are ordered by name, so that enumerations like .Mon and .Tues can be sorted */
+func (l *langObjectSymbol) greaterThan(o langObject) (bool, error) {
+	switch o.getType() {
+	case objectTypeSymbol:
+		return (l.name > o.(*langObjectSymbol).name), nil
+	default:
+		return false, nil
+	}
+}
+
+func (l *langObjectSymbol) lessThan(o langObject) (bool, error) {
+	switch o.getType() {
+	case objectTypeSymbol:
+		return (l.name < o.(*langObjectSymbol).name), nil
+	default:
+		return false, nil
+	}
+}
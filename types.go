@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"errors"
+	"strings"
 )
 
 type langObjectType int
@@ -17,8 +18,49 @@ const (
 	objectTypeReference
 	objectTypeList
 	objectTypeError
+	objectTypeDict
+	objectTypeArray
+	objectTypeRecordType
+	objectTypeRecord
+	objectTypeSymbol
 )
 
+/* The names used by the type operation, which pushes them as symbols */
+func objectTypeName(typ langObjectType) string {
+	switch typ {
+	case objectTypeString:
+		return "string"
+	case objectTypeNumber:
+		return "number"
+	case objectTypeBoolean:
+		return "boolean"
+	case objectTypeOperation:
+		return "operation"
+	case objectTypeCodeBlock:
+		return "block"
+	case objectTypeIdentifier:
+		return "identifier"
+	case objectTypeReference:
+		return "reference"
+	case objectTypeList:
+		return "list"
+	case objectTypeError:
+		return "error"
+	case objectTypeDict:
+		return "dict"
+	case objectTypeArray:
+		return "array"
+	case objectTypeRecordType:
+		return "record-type"
+	case objectTypeRecord:
+		return "record"
+	case objectTypeSymbol:
+		return "symbol"
+	}
+
+	return "unknown"
+}
+
 type langObject interface {
 	getType() langObjectType
 	getValue() interface{}
@@ -30,8 +72,48 @@ type langObject interface {
 	lessThan(langObject) (bool, error)
 }
 
+/* The order of the types when sort meets objects of different types, so that mixed lists sort consistently */
+var typeRanks = []langObjectType{
+	objectTypeNumber,
+	objectTypeString,
+	objectTypeBoolean,
+	objectTypeSymbol,
+	objectTypeList,
+	objectTypeArray,
+	objectTypeDict,
+	objectTypeRecord,
+	objectTypeRecordType,
+	objectTypeIdentifier,
+	objectTypeReference,
+	objectTypeError,
+	objectTypeOperation,
+	objectTypeCodeBlock,
+}
+
+func typeRank(typ langObjectType) int {
+	for i, ranked := range typeRanks {
+		if ranked == typ {
+			return i
+		}
+	}
+
+	return len(typeRanks)
+}
+
+/*	Comparisons between objects of different types are always false. Objects of the same type
+	are compared by value, and comparing two objects of a type that has no ordering (or, for code
+	blocks, no equality) is an error. */
+func unordered(l langObject, o langObject, operation string, typeName string) (bool, error) {
+	if l.getType() != o.getType() {
+		return false, nil
+	}
+
+	return false, fmt.Errorf("Operation %s cannot be applied to %s.", operation, typeName)
+}
+
 type stack struct {
 	contents []langObject
+	marks []int
 }
 
 func (s *stack) push(l langObject) error {
@@ -66,12 +148,118 @@ func (s *stack) peek() (langObject, error) {
 	return i, nil
 }
 
+func (s *stack) popNumber() (float64, error) {
+	obj, err := s.pop()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if obj.getType() != objectTypeNumber {
+		return 0, errors.New("Expected, but did not receive a number.")
+	}
+
+	return obj.(*langObjectNumber).val, nil
+}
+
+func (s *stack) popList() (*langObjectList, error) {
+	obj, err := s.pop()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if obj.getType() != objectTypeList {
+		return nil, errors.New("Expected, but did not get a list.")
+	}
+
+	return obj.(*langObjectList), nil
+}
+
+func (s *stack) popCodeBlock() (*langObjectCodeBlock, error) {
+	obj, err := s.pop()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if obj.getType() != objectTypeCodeBlock {
+		return nil, errors.New("Expected, but did not receive a code block.")
+	}
+
+	return obj.(*langObjectCodeBlock), nil
+}
+
+func (s *stack) popBoolean() (bool, error) {
+	obj, err := s.pop()
+
+	if err != nil {
+		return false, err
+	}
+
+	if obj.getType() != objectTypeBoolean {
+		return false, errors.New("Expected, but did not receive a boolean.")
+	}
+
+	return obj.(*langObjectBoolean).val, nil
+}
+
+/* Pops a string, naming what it was wanted for if something else is there */
+func (s *stack) popString(what string) (string, error) {
+	obj, err := s.pop()
+
+	if err != nil {
+		return "", err
+	}
+
+	if obj.getType() != objectTypeString {
+		return "", errors.New("Expected, but did not receive " + what + ".")
+	}
+
+	return obj.(*langObjectString).val, nil
+}
+
 func (s *stack) clear() error {
 	s.contents = make([]langObject, 0)
+	s.marks = nil
 
 	return nil
 }
 
+/* Marks the current height of the stack, so that everything pushed after it can be collected */
+func (s *stack) mark() {
+	s.marks = append(s.marks, len(s.contents))
+}
+
+/* Forgets the marks made since there were n of them, such as those of literals left open by an error */
+func (s *stack) unmark(n int) {
+	if len(s.marks) > n {
+		s.marks = s.marks[:n]
+	}
+}
+
+func (s *stack) collect() ([]langObject, error) {
+
+	if len(s.marks) < 1 {
+		return nil, errors.New("No stack mark to collect from.")
+	}
+
+	mark := s.marks[len(s.marks) - 1]
+	s.marks = s.marks[:len(s.marks) - 1]
+
+	/* Code inside a literal may only use what was pushed inside it */
+	if mark > len(s.contents) {
+		return nil, errors.New("The literal used items from below its start on the stack.")
+	}
+
+	collected := make([]langObject, len(s.contents) - mark)
+	copy(collected, s.contents[mark:])
+
+	s.contents = s.contents[:mark]
+
+	return collected, nil
+}
+
 func (s *stack) print() {
 	for i := len(s.contents) - 1; i >= 0; i-- {
 		fmt.Printf("%s\n", s.contents[i].toString())
@@ -228,11 +416,11 @@ func (l *langObjectBoolean) equals(o langObject) (bool, error) {
 }
 
 func (l *langObjectBoolean) lessThan(o langObject) (bool, error) {
-	return false, errors.New("Operation less than cannot be applied to boolean.")
+	return unordered(l, o, "less than", "boolean")
 }
 
 func (l *langObjectBoolean) greaterThan(o langObject) (bool, error) {
-	return false, errors.New("Operation greater than cannot be applied to boolean.")
+	return unordered(l, o, "greater than", "boolean")
 }
 
 /* Operation */
@@ -263,6 +451,117 @@ const (
 	operationTypeListSplit
 	operationTypePop
 	operationTypeInclude
+	operationTypeFormat
+	operationTypeListLength
+	operationTypeListReverse
+	operationTypeListMap
+	operationTypeListFilter
+	operationTypeListFold
+	operationTypeListReduce
+	operationTypeListNth
+	operationTypeListAppend
+	operationTypeListConcat
+	operationTypeListRange
+	operationTypeListZip
+	operationTypeListSort
+	operationTypeListAny
+	operationTypeListAll
+	operationTypeListToStack
+	operationTypeStackToList
+	operationTypeListBegin
+	operationTypeListEnd
+	operationTypeDictBegin
+	operationTypeDictEnd
+	operationTypeDictPut
+	operationTypeGet
+	operationTypeDictHas
+	operationTypeDictDelete
+	operationTypeDictKeys
+	operationTypeDictValues
+	operationTypeDictSize
+	operationTypeDictEach
+	operationTypeArrayNew
+	operationTypeArraySet
+	operationTypeArrayPush
+	operationTypeArrayPop
+	operationTypeArraySlice
+	operationTypeListToArray
+	operationTypeArrayToList
+	operationTypeClone
+	operationTypeDefineRecord
+	operationTypeNewRecord
+	operationTypeRecordWith
+	operationTypeRecordIs
+	operationTypeTypeOf
+	operationTypeIsNumber
+	operationTypeIsString
+	operationTypeIsBoolean
+	operationTypeIsBlock
+	operationTypeIsList
+	operationTypeIsReference
+	operationTypeIsError
+	operationTypeIsSymbol
+	operationTypeIsDict
+	operationTypeIsArray
+	operationTypeIsRecord
+	operationTypeSwap
+	operationTypeOver
+	operationTypeRot
+	operationTypeReverseRot
+	operationTypeNip
+	operationTypeTuck
+	operationTypePick
+	operationTypeRoll
+	operationTypeTwoDup
+	operationTypeTwoDrop
+	operationTypeDepth
+	operationTypeDip
+	operationTypeKeep
+	operationTypeBi
+	operationTypeImport
+	operationTypeExport
+	operationTypeIncludeOnce
+	operationTypePrint
+	operationTypePrintLine
+	operationTypeEmit
+	operationTypeReadLine
+	operationTypeReadAll
+	operationTypeReadFile
+	operationTypeWriteFile
+	operationTypeAppendFile
+	operationTypeListDir
+	operationTypeExists
+	operationTypeStat
+	operationTypeMakeDir
+	operationTypeRemove
+	operationTypeGlob
+	operationTypeStringToNumber
+	operationTypeJSONParse
+	operationTypeJSONStringify
+	operationTypeJSONPretty
+	operationTypeCSVRead
+	operationTypeCSVReadFile
+	operationTypeCSVWrite
+	operationTypeRegexMatch
+	operationTypeRegexFind
+	operationTypeRegexFindAll
+	operationTypeRegexReplace
+	operationTypeRegexSplit
+	operationTypeGetenv
+	operationTypeSetenv
+	operationTypeNow
+	operationTypeTimeFormat
+	operationTypeTimeParse
+	operationTypeSleep
+	operationTypeExit
+	operationTypeArgs
+	operationTypeExec
+	operationTypeExecLines
+	operationTypeRand
+	operationTypeRandInt
+	operationTypeShuffle
+	operationTypeChoice
+	operationTypeSeed
 )
 
 type langObjectOperation struct {
@@ -282,6 +581,11 @@ func (l *langObjectOperation) setValue(val interface{}) {
 }
 
 func(l *langObjectOperation) toString() string {
+	return fmt.Sprintf("<Operation: %s>", l.name())
+}
+
+/* The name of the operation, as used in error messages */
+func(l *langObjectOperation) name() string {
 	operationName := "unknown"
 
 	switch l.val {
@@ -331,9 +635,231 @@ func(l *langObjectOperation) toString() string {
 		operationName = "pop"
 	case operationTypeInclude:
 		operationName = "include"
+	case operationTypeFormat:
+		operationName = "format"
+	case operationTypeListLength:
+		operationName = "length"
+	case operationTypeListReverse:
+		operationName = "reverse"
+	case operationTypeListMap:
+		operationName = "map"
+	case operationTypeListFilter:
+		operationName = "filter"
+	case operationTypeListFold:
+		operationName = "fold"
+	case operationTypeListReduce:
+		operationName = "reduce"
+	case operationTypeListNth:
+		operationName = "nth"
+	case operationTypeListAppend:
+		operationName = "append"
+	case operationTypeListConcat:
+		operationName = "concat"
+	case operationTypeListRange:
+		operationName = "range"
+	case operationTypeListZip:
+		operationName = "zip"
+	case operationTypeListSort:
+		operationName = "sort"
+	case operationTypeListAny:
+		operationName = "any?"
+	case operationTypeListAll:
+		operationName = "all?"
+	case operationTypeListToStack:
+		operationName = "list->stack"
+	case operationTypeStackToList:
+		operationName = "stack->list"
+	case operationTypeListBegin:
+		operationName = "list begin"
+	case operationTypeListEnd:
+		operationName = "list end"
+	case operationTypeDictBegin:
+		operationName = "dictionary begin"
+	case operationTypeDictEnd:
+		operationName = "dictionary end"
+	case operationTypeDictPut:
+		operationName = "put"
+	case operationTypeGet:
+		operationName = "get"
+	case operationTypeDictHas:
+		operationName = "has?"
+	case operationTypeDictDelete:
+		operationName = "delete"
+	case operationTypeDictKeys:
+		operationName = "keys"
+	case operationTypeDictValues:
+		operationName = "values"
+	case operationTypeDictSize:
+		operationName = "size"
+	case operationTypeDictEach:
+		operationName = "each"
+	case operationTypeArrayNew:
+		operationName = "array"
+	case operationTypeArraySet:
+		operationName = "set"
+	case operationTypeArrayPush:
+		operationName = "array-push"
+	case operationTypeArrayPop:
+		operationName = "array-pop"
+	case operationTypeArraySlice:
+		operationName = "slice"
+	case operationTypeListToArray:
+		operationName = "list->array"
+	case operationTypeArrayToList:
+		operationName = "array->list"
+	case operationTypeClone:
+		operationName = "clone"
+	case operationTypeDefineRecord:
+		operationName = "defrecord"
+	case operationTypeNewRecord:
+		operationName = "new"
+	case operationTypeRecordWith:
+		operationName = "with"
+	case operationTypeRecordIs:
+		operationName = "is?"
+	case operationTypeTypeOf:
+		operationName = "type"
+	case operationTypeIsNumber:
+		operationName = "number?"
+	case operationTypeIsString:
+		operationName = "string?"
+	case operationTypeIsBoolean:
+		operationName = "boolean?"
+	case operationTypeIsBlock:
+		operationName = "block?"
+	case operationTypeIsList:
+		operationName = "list?"
+	case operationTypeIsReference:
+		operationName = "ref?"
+	case operationTypeIsError:
+		operationName = "error?"
+	case operationTypeIsSymbol:
+		operationName = "symbol?"
+	case operationTypeIsDict:
+		operationName = "dict?"
+	case operationTypeIsArray:
+		operationName = "array?"
+	case operationTypeIsRecord:
+		operationName = "record?"
+	case operationTypeSwap:
+		operationName = "swap"
+	case operationTypeOver:
+		operationName = "over"
+	case operationTypeRot:
+		operationName = "rot"
+	case operationTypeReverseRot:
+		operationName = "-rot"
+	case operationTypeNip:
+		operationName = "nip"
+	case operationTypeTuck:
+		operationName = "tuck"
+	case operationTypePick:
+		operationName = "pick"
+	case operationTypeRoll:
+		operationName = "roll"
+	case operationTypeTwoDup:
+		operationName = "2dup"
+	case operationTypeTwoDrop:
+		operationName = "2drop"
+	case operationTypeDepth:
+		operationName = "depth"
+	case operationTypeDip:
+		operationName = "dip"
+	case operationTypeKeep:
+		operationName = "keep"
+	case operationTypeBi:
+		operationName = "bi"
+	case operationTypeImport:
+		operationName = "import"
+	case operationTypeExport:
+		operationName = "export"
+	case operationTypeIncludeOnce:
+		operationName = "include-once"
+	case operationTypePrint:
+		operationName = "print"
+	case operationTypePrintLine:
+		operationName = "println"
+	case operationTypeEmit:
+		operationName = "emit"
+	case operationTypeReadLine:
+		operationName = "read-line"
+	case operationTypeReadAll:
+		operationName = "read-all"
+	case operationTypeReadFile:
+		operationName = "read-file"
+	case operationTypeWriteFile:
+		operationName = "write-file"
+	case operationTypeAppendFile:
+		operationName = "append-file"
+	case operationTypeListDir:
+		operationName = "list-dir"
+	case operationTypeExists:
+		operationName = "exists?"
+	case operationTypeStat:
+		operationName = "stat"
+	case operationTypeMakeDir:
+		operationName = "mkdir"
+	case operationTypeRemove:
+		operationName = "remove"
+	case operationTypeGlob:
+		operationName = "glob"
+	case operationTypeStringToNumber:
+		operationName = "string->number"
+	case operationTypeJSONParse:
+		operationName = "json-parse"
+	case operationTypeJSONStringify:
+		operationName = "json-stringify"
+	case operationTypeJSONPretty:
+		operationName = "json-pretty"
+	case operationTypeCSVRead:
+		operationName = "csv-read"
+	case operationTypeCSVReadFile:
+		operationName = "csv-read-file"
+	case operationTypeCSVWrite:
+		operationName = "csv-write"
+	case operationTypeRegexMatch:
+		operationName = "re-match?"
+	case operationTypeRegexFind:
+		operationName = "re-find"
+	case operationTypeRegexFindAll:
+		operationName = "re-find-all"
+	case operationTypeRegexReplace:
+		operationName = "re-replace"
+	case operationTypeRegexSplit:
+		operationName = "re-split"
+	case operationTypeGetenv:
+		operationName = "getenv"
+	case operationTypeSetenv:
+		operationName = "setenv"
+	case operationTypeNow:
+		operationName = "now"
+	case operationTypeTimeFormat:
+		operationName = "time-format"
+	case operationTypeTimeParse:
+		operationName = "time-parse"
+	case operationTypeSleep:
+		operationName = "sleep"
+	case operationTypeExit:
+		operationName = "exit"
+	case operationTypeArgs:
+		operationName = "args"
+	case operationTypeExec:
+		operationName = "exec"
+	case operationTypeExecLines:
+		operationName = "exec-lines"
+	case operationTypeRand:
+		operationName = "rand"
+	case operationTypeRandInt:
+		operationName = "rand-int"
+	case operationTypeShuffle:
+		operationName = "shuffle"
+	case operationTypeChoice:
+		operationName = "choice"
+	case operationTypeSeed:
+		operationName = "seed"
 	}
 
-	return fmt.Sprintf("<Operation: %s>", operationName)
+	return operationName
 }
 
 func (l *langObjectOperation) copy() langObject {
@@ -350,11 +876,11 @@ func (l *langObjectOperation) equals(o langObject) (bool, error){
 }
 
 func (l *langObjectOperation) greaterThan(o langObject) (bool, error) {
-	return false, errors.New("Operation greater than cannot be applied to operation.")
+	return unordered(l, o, "greater than", "operation")
 }
 
 func (l *langObjectOperation) lessThan(o langObject) (bool, error) {
-	return false, errors.New("Operation less than cannot be applied to operation.")
+	return unordered(l, o, "less than", "operation")
 }
 
 /* Code Block */
@@ -362,6 +888,22 @@ func (l *langObjectOperation) lessThan(o langObject) (bool, error) {
 type langObjectCodeBlock struct {
 	code []langObject
 	parentScope *variableScope
+	effect *stackEffect
+	params []string
+	lines []int
+	file string
+}
+
+/* A stack effect declaration such as ( a b -- c ), naming a code block's inputs and outputs */
+type stackEffect struct {
+	inputs []string
+	outputs []string
+}
+
+func (e *stackEffect) toString() string {
+	names := append(append(append([]string{}, e.inputs...), "--"), e.outputs...)
+
+	return "( " + strings.Join(names, " ") + " )"
 }
 
 func (l *langObjectCodeBlock) getType() langObjectType {
@@ -377,7 +919,25 @@ func (l *langObjectCodeBlock) setValue(code interface{}) {
 }
 
 func (l * langObjectCodeBlock) toString() string {
-	return "<CodeBlock>"
+	header := make([]string, 0)
+
+	if l.params != nil {
+		header = append(header, l.paramsString())
+	}
+
+	if l.effect != nil {
+		header = append(header, l.effect.toString())
+	}
+
+	if len(header) == 0 {
+		return "<CodeBlock>"
+	}
+
+	return "<CodeBlock " + strings.Join(header, " ") + ">"
+}
+
+func (l *langObjectCodeBlock) paramsString() string {
+	return "|" + strings.Join(l.params, " ") + "|"
 }
 
 func (l *langObjectCodeBlock) print(tabs int) {
@@ -403,19 +963,23 @@ func (l *langObjectCodeBlock) copy() langObject {
 		newCode = append(newCode, obj.copy())
 	}
 
-	return &langObjectCodeBlock{newCode,l.parentScope,}
+	return &langObjectCodeBlock{newCode,l.parentScope,l.effect,l.params,l.lines,l.file,}
 }
 
 func (l *langObjectCodeBlock) equals(o langObject) (bool, error) {
+	if o.getType() != objectTypeCodeBlock {
+		return false, nil
+	}
+
 	return false, errors.New("Code block objects are not comparable.")
 }
 
 func (l *langObjectCodeBlock) greaterThan(o langObject) (bool, error) {
-	return false, errors.New("Code block objects are not comparable.")
+	return unordered(l, o, "greater than", "block")
 }
 
 func (l *langObjectCodeBlock) lessThan(o langObject) (bool, error) {
-	return false, errors.New("Code block objects are not comparable.")
+	return unordered(l, o, "less than", "block")
 }
 
 func (l *langObjectCodeBlock) handleParentVariables(st *symbolTable) error {
@@ -472,7 +1036,6 @@ const (
 	identifierReference
 	identifierReferenceAt
 	identifierCall
-	identifierName
 )
 
 type langObjectIdentifier struct {
@@ -497,7 +1060,7 @@ func (l *langObjectIdentifier) toString() string {
 
 	switch l.typ {
 	case identifierReference:
-		prefix = "."
+		prefix = "'"
 	case identifierReferenceAt:
 		prefix = "@"
 	case identifierCall:
@@ -523,11 +1086,11 @@ func (l *langObjectIdentifier) equals(o langObject) (bool, error) {
 }
 
 func (l *langObjectIdentifier) greaterThan(o langObject) (bool, error) {
-	return false, errors.New("Operation greater than cannot be applied to identifier.")
+	return unordered(l, o, "greater than", "identifier")
 }
 
 func (l *langObjectIdentifier) lessThan(o langObject) (bool, error) {
-	return false, errors.New("Operation less than cannot be applied to identifier.")
+	return unordered(l, o, "less than", "identifier")
 }
 
 /* Reference */
@@ -566,11 +1129,11 @@ func (l *langObjectReference) equals(o langObject) (bool, error) {
 }
 
 func (l *langObjectReference) greaterThan(o langObject) (bool, error) {
-	return false, errors.New("Operation greater than cannot be applied to reference.")
+	return unordered(l, o, "greater than", "reference")
 }
 
 func (l *langObjectReference) lessThan(o langObject) (bool, error) {
-	return false, errors.New("Operation less than cannot be applied to reference.")
+	return unordered(l, o, "less than", "reference")
 }
 
 /* Error */
@@ -611,11 +1174,11 @@ func (l *langObjectError) equals(o langObject) (bool, error) {
 }
 
 func (l *langObjectError) greaterThan(o langObject) (bool, error) {
-	return false, errors.New("Operation greater than cannot be applied to error.")
+	return unordered(l, o, "greater than", "error")
 }
 
 func (l *langObjectError) lessThan(o langObject) (bool, error) {
-	return false, errors.New("Operation less than cannot be applied to error.")
+	return unordered(l, o, "less than", "error")
 }
 
 /* Lists */
@@ -646,36 +1209,84 @@ func (l *langObjectList) toString() string {
 	}
 }
 
+/*	Lists are immutable, so a list never needs to be copied. Every list built with :: shares its
+	tail with the list it was built from, which keeps :: and split constant time. */
 func (l *langObjectList) copy() langObject {
+	return l
+}
 
-	var head_copied langObject
-	tail_copied := &langObjectList{}
+func newList(elements []langObject) *langObjectList {
+	list := &langObjectList{true, nil, nil,}
 
-	if l.tail != nil {
-		tail_copied = l.tail.copy().(*langObjectList)
+	for i := len(elements) - 1; i >= 0; i-- {
+		list = &langObjectList{false, elements[i], list,}
 	}
 
-	if l.head != nil {
-		head_copied = l.head.copy()
-	}
+	return list
+}
+
+func (l *langObjectList) elements() []langObject {
+	elements := make([]langObject, 0)
 
-	return &langObjectList{
-		l.empty,
-		head_copied,
-		tail_copied,
+	for list := l; list != nil && !list.empty; list = list.tail {
+		elements = append(elements, list.head)
 	}
 
+	return elements
 }
 
+/* Lists are equal if their elements are pairwise equal */
 func (l *langObjectList) equals(obj langObject) (bool, error) {
-	return false, errors.New("Operation equals cannot be applied to list.")
+	if obj.getType() != objectTypeList {
+		return false, nil
+	}
+
+	elements, otherElements := l.elements(), obj.(*langObjectList).elements()
+
+	if len(elements) != len(otherElements) {
+		return false, nil
+	}
+
+	for i := range elements {
+		equal, err := elements[i].equals(otherElements[i])
+
+		if err != nil || !equal {
+			return false, err
+		}
+	}
+
+	return true, nil
 }
 
 func (l *langObjectList) greaterThan(obj langObject) (bool, error) {
-	return false, errors.New("Operation greater than cannot be applied to list.")
+	if obj.getType() != objectTypeList {
+		return false, nil
+	}
+
+	return obj.(*langObjectList).lessThan(l)
 }
 
+/*	Lists are ordered lexicographically: the first pair of elements that are not equal decides
+	the order, and a list that is a prefix of another comes before it. */
 func (l *langObjectList) lessThan(obj langObject) (bool, error) {
-	return false, errors.New("Operation less than cannot be applied to list.")
+	if obj.getType() != objectTypeList {
+		return false, nil
+	}
+
+	elements, otherElements := l.elements(), obj.(*langObjectList).elements()
+
+	for i := 0; i < len(elements) && i < len(otherElements); i++ {
+		equal, eqErr := elements[i].equals(otherElements[i])
+
+		if eqErr != nil {
+			return false, eqErr
+		}
+
+		if !equal {
+			return elements[i].lessThan(otherElements[i])
+		}
+	}
+
+	return len(elements) < len(otherElements), nil
 }
 
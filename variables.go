@@ -12,6 +12,7 @@ type symbolTableEntry struct {
 
 type symbolTable struct {
 	symbols map[uint64]*symbolTableEntry
+	released []uint64
 }
 
 func (s *symbolTable) insert(value langObject) (uint64, error) {
@@ -71,17 +72,24 @@ func (s *symbolTable) decReference(key uint64) error {
 
 	symbol.references--
 
+	/* Remember the entry so that cleanUp only has to look at entries that may be garbage */
+	if symbol.references < 1 {
+		s.released = append(s.released, key)
+	}
+
 	return nil
 }
 
 func (s *symbolTable) cleanUp() error {
 
-	for k, v := range s.symbols {
-		if v.references < 1 {
+	for _, k := range s.released {
+		if v, ok := s.symbols[k]; ok && v.references < 1 {
 			delete(s.symbols, k)
 		}
 	}
 
+	s.released = s.released[:0]
+
 	return nil
 
 }
@@ -136,6 +144,28 @@ func (v *variableScope) setLocal(name string, key uint64) error {
 	return nil
 }
 
+/*	Binds a parameter of a called block in the call's own scope. Unlike asn, it never replaces a
+	variable further out, and unlike lasn, the parameter can be seen by nested blocks. */
+func (v *variableScope) bindParameter(name string, val langObject, st *symbolTable) error {
+
+	/* A name repeated in the parameter list is bound again */
+	if variable, ok := v.variables[name]; ok {
+		err1 := st.decReference(variable.key)
+
+		if err1 != nil {
+			return err1
+		}
+	}
+
+	key, err2 := st.insert(val)
+
+	if err2 != nil {
+		return err2
+	}
+
+	return v.set(name, key)
+}
+
 /*func (v *variableScope) setGlobal(name string, key uint64) error {
 	
 	parent := v.parent